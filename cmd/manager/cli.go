@@ -0,0 +1,302 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"manager"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// cli is a client of the admin api of a service
+type cli struct {
+	address string
+	prefix  string
+	token   string
+	output  string
+	client  *http.Client
+	out     io.Writer
+}
+
+func newCli(args []string, out io.Writer) (*cli, []string, error) {
+	c := &cli{out: out}
+
+	flags := flag.NewFlagSet("manager", flag.ContinueOnError)
+	flags.SetOutput(out)
+	flags.StringVar(&c.address, "address", envOr("MANAGER_ADMIN_ADDRESS", "http://localhost:8090"), "address of the admin api")
+	flags.StringVar(&c.prefix, "prefix", "/admin", "prefix of the routes of the admin api")
+	flags.StringVar(&c.token, "token", os.Getenv("MANAGER_ADMIN_TOKEN"), "token of the admin api")
+	flags.StringVar(&c.output, "output", outputTable, "output format, table or json")
+	timeout := flags.Duration("timeout", 30*time.Second, "timeout of the requests")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if c.output != outputTable && c.output != outputJSON {
+		return nil, nil, fmt.Errorf("invalid output %s, it should be table or json", c.output)
+	}
+
+	if !strings.Contains(c.address, "://") {
+		c.address = "http://" + c.address
+	}
+	c.client = &http.Client{Timeout: *timeout}
+
+	return c, flags.Args(), nil
+}
+
+func (c *cli) status() error {
+	var status manager.AdminStatus
+	raw, err := c.request(http.MethodGet, "/status", nil, &status)
+	if err != nil {
+		return err
+	}
+
+	return c.print(raw, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "STARTED\t%t\n", status.Started)
+		fmt.Fprintf(w, "LOG LEVEL\t%s\n", status.LogLevel)
+
+		states := make([]string, 0, len(status.Components))
+		for state := range status.Components {
+			states = append(states, string(state))
+		}
+		sort.Strings(states)
+
+		for _, state := range states {
+			fmt.Fprintf(w, "%s\t%d\n", strings.ToUpper(state), status.Components[manager.ComponentState(state)])
+		}
+	})
+}
+
+func (c *cli) components(args []string) error {
+	query := url.Values{}
+	if len(args) > 0 {
+		query.Set("kind", args[0])
+	}
+
+	var components []*manager.ComponentInfo
+	raw, err := c.request(http.MethodGet, "/components", query, &components)
+	if err != nil {
+		return err
+	}
+
+	return c.print(raw, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "KIND\tKEY\tSTATE")
+		for _, component := range components {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", component.Kind, component.Key, component.State)
+		}
+	})
+}
+
+func (c *cli) control(action string, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: %s <kind> <key>", action)
+	}
+
+	var component manager.ComponentInfo
+	raw, err := c.request(http.MethodPost, "/components/"+action, url.Values{"kind": {args[0]}, "key": {args[1]}}, &component)
+	if err != nil {
+		return err
+	}
+
+	return c.print(raw, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "KIND\tKEY\tSTATE")
+		fmt.Fprintf(w, "%s\t%s\t%s\n", component.Kind, component.Key, component.State)
+	})
+}
+
+func (c *cli) queue(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: queue dump|drain <worklist>")
+	}
+
+	switch args[0] {
+	case "dump":
+		raw, err := c.request(http.MethodGet, "/worklists/dump", url.Values{"key": {args[1]}}, nil)
+		if err != nil {
+			return err
+		}
+
+		// the dump is printed as indented json on both outputs
+		return c.printJSON(raw)
+	case "drain":
+		query := url.Values{"key": {args[1]}}
+		if len(args) > 2 {
+			query.Set("timeout", args[2])
+		}
+
+		raw, err := c.request(http.MethodPost, "/worklists/drain", query, nil)
+		if err != nil {
+			return err
+		}
+
+		return c.print(raw, func(w *tabwriter.Writer) {
+			fmt.Fprintf(w, "work list %s drained\n", args[1])
+		})
+	default:
+		return fmt.Errorf("unknown queue command %s", args[0])
+	}
+}
+
+func (c *cli) config(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: config get <key> [config] | config reload")
+	}
+
+	switch args[0] {
+	case "get":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: config get <key> [config]")
+		}
+
+		query := url.Values{"key": {args[1]}}
+		if len(args) > 2 {
+			query.Set("config", args[2])
+		}
+
+		var result struct {
+			Config string      `json:"config"`
+			Key    string      `json:"key"`
+			Value  interface{} `json:"value"`
+		}
+		raw, err := c.request(http.MethodGet, "/configs/get", query, &result)
+		if err != nil {
+			return err
+		}
+
+		return c.print(raw, func(w *tabwriter.Writer) {
+			value, _ := json.Marshal(result.Value)
+			fmt.Fprintln(w, "CONFIG\tKEY\tVALUE")
+			fmt.Fprintf(w, "%s\t%s\t%s\n", result.Config, result.Key, value)
+		})
+	case "reload":
+		raw, err := c.request(http.MethodPost, "/configs/reload", nil, nil)
+		if err != nil {
+			return err
+		}
+
+		return c.print(raw, func(w *tabwriter.Writer) {
+			fmt.Fprintln(w, "configs reloaded")
+		})
+	default:
+		return fmt.Errorf("unknown config command %s", args[0])
+	}
+}
+
+func (c *cli) logLevel(args []string) error {
+	var result struct {
+		Level string `json:"level"`
+	}
+
+	var raw []byte
+	var err error
+	switch {
+	case len(args) == 1 && args[0] == "get":
+		raw, err = c.request(http.MethodGet, "/log/level", nil, &result)
+	case len(args) == 2 && args[0] == "set":
+		raw, err = c.request(http.MethodPut, "/log/level", url.Values{"level": {args[1]}}, &result)
+	default:
+		return fmt.Errorf("usage: loglevel get | loglevel set <level>")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return c.print(raw, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "LOG LEVEL\t%s\n", result.Level)
+	})
+}
+
+// request calls the admin api, decoding the response into the result and returning it raw
+func (c *cli) request(method, path string, query url.Values, result interface{}) ([]byte, error) {
+	address := strings.TrimSuffix(c.address, "/") + strings.TrimSuffix(c.prefix, "/") + path
+	if len(query) > 0 {
+		address += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(method, address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(raw, &failure) == nil && failure.Error != "" {
+			return nil, fmt.Errorf("%s (%d)", failure.Error, resp.StatusCode)
+		}
+
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(raw, result); err != nil {
+			return nil, fmt.Errorf("invalid response: %s", err)
+		}
+	}
+
+	return raw, nil
+}
+
+// print writes the raw response on the json output, or the table otherwise
+func (c *cli) print(raw []byte, table func(w *tabwriter.Writer)) error {
+	if c.output == outputJSON {
+		return c.printJSON(raw)
+	}
+
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	table(w)
+
+	return w.Flush()
+}
+
+func (c *cli) printJSON(raw []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(c.out)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+
+	return fallback
+}
@@ -0,0 +1,57 @@
+// Command manager operates the services built on the manager, through their admin api.
+//
+//	manager [flags] status
+//	manager [flags] components [kind]
+//	manager [flags] start|stop|restart|pause|resume <kind> <key>
+//	manager [flags] queue dump <worklist>
+//	manager [flags] queue drain <worklist> [timeout]
+//	manager [flags] config get <key> [config]
+//	manager [flags] config reload
+//	manager [flags] loglevel get
+//	manager [flags] loglevel set <level>
+//
+// the address and the token of the admin api are also read from MANAGER_ADMIN_ADDRESS and MANAGER_ADMIN_TOKEN
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command of the arguments, writing its output
+func run(args []string, out io.Writer) error {
+	cli, args, err := newCli(args, out)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("a command is required, as status, components, stop, queue, config or loglevel")
+	}
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "status":
+		return cli.status()
+	case "components":
+		return cli.components(args)
+	case "start", "stop", "restart", "pause", "resume":
+		return cli.control(command, args)
+	case "queue":
+		return cli.queue(args)
+	case "config":
+		return cli.config(args)
+	case "loglevel":
+		return cli.logLevel(args)
+	default:
+		return fmt.Errorf("unknown command %s", command)
+	}
+}
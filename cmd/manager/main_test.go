@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"manager"
+)
+
+func TestCli(t *testing.T) {
+	m := manager.NewManager(manager.WithRunInBackground(true))
+
+	file := filepath.Join(t.TempDir(), "app.json")
+	if err := ioutil.WriteFile(file, []byte(`{"app": {"name": "cli"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := m.NewSimpleConfig(file, &map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddConfig("app", config); err != nil {
+		t.Fatal(err)
+	}
+
+	worklist := m.NewSimpleWorkList(manager.NewWorkListConfig("queue", 1, 1, time.Millisecond, manager.FIFO),
+		func(id string, data interface{}) error { return nil }, nil, nil)
+	if err := m.AddWorkList("queue", worklist); err != nil {
+		t.Fatal(err)
+	}
+
+	process := m.NewSupervisedProcess("process", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	if err := m.AddProcess("process", process); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer m.Stop()
+
+	auth, err := manager.TokenAuth("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(m.NewAdmin(manager.WithAdminAuth(auth)).Handler())
+	defer server.Close()
+
+	execute := func(args ...string) (string, error) {
+		var out bytes.Buffer
+		err := run(append([]string{"-address", server.URL, "-token", "secret"}, args...), &out)
+		return out.String(), err
+	}
+
+	out, err := execute("components")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"KIND", "worklist  queue    running", "process   process  running"} {
+		if !strings.Contains(out, expected) {
+			t.Fatalf("expected %q on the components table, got\n%s", expected, out)
+		}
+	}
+
+	if out, err = execute("stop", "process", "process"); err != nil || !strings.Contains(out, "stopped") {
+		t.Fatalf("expected the process to be stopped, got %s %v", out, err)
+	}
+
+	out, err = execute("-output", "json", "status")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var status manager.AdminStatus
+	if err := json.Unmarshal([]byte(out), &status); err != nil {
+		t.Fatal(err)
+	}
+	if !status.Started || status.Components[manager.StateStopped] != 1 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	if out, err = execute("queue", "dump", "queue"); err != nil || !strings.Contains(out, `"queue"`) {
+		t.Fatalf("expected the dump of the queue, got %s %v", out, err)
+	}
+
+	if out, err = execute("queue", "drain", "queue", "1s"); err != nil || !strings.Contains(out, "drained") {
+		t.Fatalf("expected the queue to be drained, got %s %v", out, err)
+	}
+
+	if out, err = execute("config", "get", "app.name"); err != nil || !strings.Contains(out, `"cli"`) {
+		t.Fatalf("expected the config value, got %s %v", out, err)
+	}
+
+	if out, err = execute("config", "reload"); err != nil {
+		t.Fatalf("expected the configs to be reloaded, got %s %v", out, err)
+	}
+
+	if out, err = execute("loglevel", "set", "debug"); err != nil || !strings.Contains(out, "debug") {
+		t.Fatalf("expected the debug log level, got %s %v", out, err)
+	}
+
+	if _, err = execute("stop", "process", "missing"); err == nil {
+		t.Fatal("expected an error stopping a missing component")
+	}
+
+	if err = run([]string{"-address", server.URL, "status"}, ioutil.Discard); err == nil {
+		t.Fatal("expected an unauthorized request without the token")
+	}
+}
@@ -2,11 +2,57 @@ package manager
 
 import "fmt"
 
-// AppConfig ...
+// AppConfig describes the manager and its components by key, to be created by NewManagerFromConfig
 type AppConfig struct {
-	Manager *ManagerConfig `json:"manager"`
+	Manager       *ManagerConfig                 `json:"manager"`
+	DBs           map[string]*DBConfig           `json:"dbs"`
+	Redis         map[string]*RedisConfig        `json:"redis"`
+	NSQ           *NSQComponentsConfig           `json:"nsq"`
+	Rabbitmq      *RabbitmqComponentsConfig      `json:"rabbitmq"`
+	Webs          map[string]*WebConfig          `json:"webs"`
+	WorkLists     map[string]*WorkListConfig     `json:"worklists"`
+	BulkWorkLists map[string]*BulkWorkListConfig `json:"bulk_worklists"`
+	Gateways      map[string]*GatewayConfig      `json:"gateways"`
 }
 
+// NSQComponentsConfig ...
+type NSQComponentsConfig struct {
+	Producers map[string]*NSQConfig `json:"producers"`
+	Consumers map[string]*NSQConfig `json:"consumers"`
+}
+
+// RabbitmqComponentsConfig ...
+type RabbitmqComponentsConfig struct {
+	Producers map[string]*RabbitmqConfig         `json:"producers"`
+	Consumers map[string]*RabbitmqConsumerConfig `json:"consumers"`
+}
+
+// RabbitmqConsumerConfig ...
+type RabbitmqConsumerConfig struct {
+	RabbitmqConfig
+	Queue      string `json:"queue" validate:"required"`
+	BindingKey string `json:"binding_key"`
+	Tag        string `json:"tag"`
+}
+
+// WebType ...
+type WebType string
+
+const (
+	WebTypeServer WebType = "web"
+	WebTypeEcho   WebType = "echo"
+	WebTypeHttp   WebType = "http"
+)
+
+// WebConfig ...
+type WebConfig struct {
+	Type WebType `json:"type" validate:"omitempty,oneof=web echo http"`
+	Host string  `json:"host" validate:"required"`
+}
+
+// GatewayConfig ...
+type GatewayConfig struct{}
+
 // ManagerConfig ...
 type ManagerConfig struct {
 	Log struct {
@@ -0,0 +1,272 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+// configAccessors are the typed accessors of a config over its viper, that tell the missing keys
+// and the values of other types apart from the zero values, without panicking
+type configAccessors struct {
+	viper func() *viper.Viper
+}
+
+// Has tells if the key is set on the config
+func (accessors configAccessors) Has(key string) bool {
+	return accessors.viper().IsSet(key)
+}
+
+// Unmarshal unmarshals the sub-tree of the key into the target, by the json tags of the target,
+// or the whole config when the key is empty
+func (accessors configAccessors) Unmarshal(key string, target interface{}) error {
+	var value interface{}
+	if key == "" {
+		value = accessors.viper().AllSettings()
+	} else {
+		var err error
+		if value, err = accessors.value(key); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("error reading the config key %s: %s", key, err)
+	}
+
+	if err = json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("error unmarshalling the config key %s: %s", key, err)
+	}
+
+	return nil
+}
+
+// GetStringE returns the value of the key, or an error when it isn't set or isn't a string
+func (accessors configAccessors) GetStringE(key string) (string, error) {
+	value, err := accessors.value(key)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := cast.ToStringE(value)
+	return result, accessors.typeError(key, "a string", err)
+}
+
+// GetBoolE returns the value of the key, or an error when it isn't set or isn't a bool
+func (accessors configAccessors) GetBoolE(key string) (bool, error) {
+	value, err := accessors.value(key)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := cast.ToBoolE(value)
+	return result, accessors.typeError(key, "a bool", err)
+}
+
+// GetIntE returns the value of the key, or an error when it isn't set or isn't an int
+func (accessors configAccessors) GetIntE(key string) (int, error) {
+	value, err := accessors.value(key)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := cast.ToIntE(value)
+	return result, accessors.typeError(key, "an int", err)
+}
+
+// GetInt64E returns the value of the key, or an error when it isn't set or isn't an int64
+func (accessors configAccessors) GetInt64E(key string) (int64, error) {
+	value, err := accessors.value(key)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := cast.ToInt64E(value)
+	return result, accessors.typeError(key, "an int64", err)
+}
+
+// GetFloat64E returns the value of the key, or an error when it isn't set or isn't a float64
+func (accessors configAccessors) GetFloat64E(key string) (float64, error) {
+	value, err := accessors.value(key)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := cast.ToFloat64E(value)
+	return result, accessors.typeError(key, "a float64", err)
+}
+
+// GetTimeE returns the value of the key, or an error when it isn't set or isn't a time
+func (accessors configAccessors) GetTimeE(key string) (time.Time, error) {
+	value, err := accessors.value(key)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	result, err := cast.ToTimeE(value)
+	return result, accessors.typeError(key, "a time", err)
+}
+
+// GetDurationE returns the value of the key, or an error when it isn't set or isn't a duration, as 5s or 1m30s
+func (accessors configAccessors) GetDurationE(key string) (time.Duration, error) {
+	value, err := accessors.value(key)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := cast.ToDurationE(value)
+	return result, accessors.typeError(key, "a duration", err)
+}
+
+// GetStringSliceE returns the value of the key, or an error when it isn't set or isn't a list of strings
+func (accessors configAccessors) GetStringSliceE(key string) ([]string, error) {
+	value, err := accessors.value(key)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := cast.ToStringSliceE(value)
+	return result, accessors.typeError(key, "a list of strings", err)
+}
+
+// GetStringMapE returns the value of the key, or an error when it isn't set or isn't an object
+func (accessors configAccessors) GetStringMapE(key string) (map[string]interface{}, error) {
+	value, err := accessors.value(key)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := cast.ToStringMapE(value)
+	return result, accessors.typeError(key, "an object", err)
+}
+
+// GetStringMapStringE returns the value of the key, or an error when it isn't set or isn't an object of strings
+func (accessors configAccessors) GetStringMapStringE(key string) (map[string]string, error) {
+	value, err := accessors.value(key)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := cast.ToStringMapStringE(value)
+	return result, accessors.typeError(key, "an object of strings", err)
+}
+
+// GetStringMapStringSliceE returns the value of the key, or an error when it isn't set or isn't an object of lists of strings
+func (accessors configAccessors) GetStringMapStringSliceE(key string) (map[string][]string, error) {
+	value, err := accessors.value(key)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := cast.ToStringMapStringSliceE(value)
+	return result, accessors.typeError(key, "an object of lists of strings", err)
+}
+
+// GetStringOr returns the value of the key, or the default when it isn't set or isn't a string
+func (accessors configAccessors) GetStringOr(key string, defaultValue string) string {
+	if value, err := accessors.GetStringE(key); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// GetBoolOr returns the value of the key, or the default when it isn't set or isn't a bool
+func (accessors configAccessors) GetBoolOr(key string, defaultValue bool) bool {
+	if value, err := accessors.GetBoolE(key); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// GetIntOr returns the value of the key, or the default when it isn't set or isn't an int
+func (accessors configAccessors) GetIntOr(key string, defaultValue int) int {
+	if value, err := accessors.GetIntE(key); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// GetInt64Or returns the value of the key, or the default when it isn't set or isn't an int64
+func (accessors configAccessors) GetInt64Or(key string, defaultValue int64) int64 {
+	if value, err := accessors.GetInt64E(key); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// GetFloat64Or returns the value of the key, or the default when it isn't set or isn't a float64
+func (accessors configAccessors) GetFloat64Or(key string, defaultValue float64) float64 {
+	if value, err := accessors.GetFloat64E(key); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// GetTimeOr returns the value of the key, or the default when it isn't set or isn't a time
+func (accessors configAccessors) GetTimeOr(key string, defaultValue time.Time) time.Time {
+	if value, err := accessors.GetTimeE(key); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// GetDurationOr returns the value of the key, or the default when it isn't set or isn't a duration
+func (accessors configAccessors) GetDurationOr(key string, defaultValue time.Duration) time.Duration {
+	if value, err := accessors.GetDurationE(key); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// GetStringSliceOr returns the value of the key, or the default when it isn't set or isn't a list of strings
+func (accessors configAccessors) GetStringSliceOr(key string, defaultValue []string) []string {
+	if value, err := accessors.GetStringSliceE(key); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// GetStringMapOr returns the value of the key, or the default when it isn't set or isn't an object
+func (accessors configAccessors) GetStringMapOr(key string, defaultValue map[string]interface{}) map[string]interface{} {
+	if value, err := accessors.GetStringMapE(key); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// GetStringMapStringOr returns the value of the key, or the default when it isn't set or isn't an object of strings
+func (accessors configAccessors) GetStringMapStringOr(key string, defaultValue map[string]string) map[string]string {
+	if value, err := accessors.GetStringMapStringE(key); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// GetStringMapStringSliceOr returns the value of the key, or the default when it isn't set or isn't an object of lists of strings
+func (accessors configAccessors) GetStringMapStringSliceOr(key string, defaultValue map[string][]string) map[string][]string {
+	if value, err := accessors.GetStringMapStringSliceE(key); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// value returns the value of the key, or an error when it isn't set
+func (accessors configAccessors) value(key string) (interface{}, error) {
+	viper := accessors.viper()
+	if !viper.IsSet(key) {
+		return nil, fmt.Errorf("the config key %s isn't set", key)
+	}
+
+	return viper.Get(key), nil
+}
+
+func (accessors configAccessors) typeError(key, kind string, err error) error {
+	if err != nil {
+		return fmt.Errorf("the config key %s isn't %s: %s", key, kind, err)
+	}
+	return nil
+}
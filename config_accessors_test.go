@@ -0,0 +1,56 @@
+package manager
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigAccessors(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "app.yml")
+	content := "redis:\n  cache:\n    host: localhost\n    port: 6379\ntimeout: 5s\nname: orders\ntags: [a, b]\n"
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := NewManager().NewSimpleConfig(file, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !config.Has("redis.cache.port") || config.Has("redis.cache.password") {
+		t.Fatal("expected only the keys of the file to be set")
+	}
+
+	if config.GetIntOr("redis.cache.port", 1) != 6379 || config.GetIntOr("redis.cache.database", 3) != 3 || config.GetIntOr("name", 4) != 4 {
+		t.Fatal("expected the values of the keys, and the defaults of the missing ones and of the other types")
+	}
+
+	if config.GetDurationOr("timeout", time.Second) != 5*time.Second || config.GetStringOr("missing", "default") != "default" ||
+		strings.Join(config.GetStringSliceOr("tags", nil), ",") != "a,b" {
+		t.Fatal("expected the typed values with the defaults")
+	}
+
+	if _, err = config.GetIntE("redis.cache.database"); err == nil || !strings.Contains(err.Error(), "redis.cache.database") {
+		t.Fatalf("expected the missing key to be reported, got %v", err)
+	}
+
+	if _, err = config.GetIntE("name"); err == nil || !strings.Contains(err.Error(), "isn't an int") {
+		t.Fatalf("expected the value of another type to be reported, got %v", err)
+	}
+
+	if port, err := config.GetIntE("redis.cache.port"); err != nil || port != 6379 {
+		t.Fatalf("expected the port, got %d %v", port, err)
+	}
+
+	redis := &RedisConfig{}
+	if err = config.Unmarshal("redis.cache", redis); err != nil || redis.Host != "localhost" || redis.Port != 6379 {
+		t.Fatalf("expected the sub-tree of the key, got %+v %v", redis, err)
+	}
+
+	if err = config.Unmarshal("redis.main", redis); err == nil {
+		t.Fatal("expected the missing sub-tree to be reported")
+	}
+}
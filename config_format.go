@@ -0,0 +1,287 @@
+package manager
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl"
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v2"
+)
+
+// decodeConfigFile parses the data of the file into a map, with the format of the file extension.
+// the numbers of json are kept as json.Number, so the big integers don't lose precision
+func decodeConfigFile(file string, data []byte) (map[string]interface{}, error) {
+	var values interface{}
+	var err error
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&values)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		var tree *toml.Tree
+		if tree, err = toml.LoadBytes(data); err == nil {
+			values = tree.ToMap()
+		}
+	case ".env":
+		values, err = decodeEnvFile(data)
+	case ".hcl":
+		if err = hcl.Unmarshal(data, &values); err == nil {
+			values = mergeHCLBlocks(values)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format of the config file %s", file)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %s", file, err)
+	}
+
+	values = normalizeConfigValue(values)
+	if values == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	m, ok := values.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid config file %s: it isn't an object", file)
+	}
+
+	return m, nil
+}
+
+// encodeConfigFile encodes the object with the format of the file extension, through its json,
+// and as indented json when the extension isn't known
+func encodeConfigFile(file string, obj interface{}) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".hcl":
+		// the hcl library only parses the files, so they can't be written back on their own format
+		return nil, fmt.Errorf("the config file %s can't be saved, the hcl files are read only", file)
+	case ".yaml", ".yml", ".toml", ".env":
+	default:
+		return json.MarshalIndent(obj, "", "    ")
+	}
+
+	values, err := configValues(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	m, ok := values.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the config of the file %s isn't an object", file)
+	}
+
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return yaml.Marshal(m)
+	case ".toml":
+		// toml doesn't have nulls, so the keys without value are left out
+		tree, err := toml.TreeFromMap(removeConfigNulls(m).(map[string]interface{}))
+		if err != nil {
+			return nil, err
+		}
+
+		text, err := tree.ToTomlString()
+		return []byte(text), err
+	default:
+		return encodeEnvFile(m)
+	}
+}
+
+// mergeHCLBlocks converts the blocks of hcl, that are decoded as lists of objects, to the objects of the other formats,
+// merging the blocks with the same name as hcl does
+func mergeHCLBlocks(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []map[string]interface{}:
+		m := make(map[string]interface{})
+		for _, block := range v {
+			for key, item := range block {
+				m[key] = mergeHCLBlocks(item)
+			}
+		}
+		return m
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = mergeHCLBlocks(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = mergeHCLBlocks(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// writeConfigFile writes the object to the config file with the format of its extension
+func writeConfigFile(fileName string, obj interface{}) error {
+	if !Exists(fileName) {
+		fileName = global[path_key].(string) + fileName
+	}
+
+	data, err := encodeConfigFile(fileName, obj)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(fileName, data, 0644)
+}
+
+// configJSON returns the json of the config file, converting it from the format of the file
+func configJSON(file string, data []byte) ([]byte, error) {
+	values, err := decodeConfigFile(file, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(values)
+}
+
+// configValues converts the object to the maps and slices of its json, with the numbers as int64 or float64
+func configValues(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var values interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+
+	return convertConfigNumbers(values), nil
+}
+
+func convertConfigNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = convertConfigNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertConfigNumbers(item)
+		}
+	case json.Number:
+		if number, err := v.Int64(); err == nil {
+			return number
+		}
+		if number, err := v.Float64(); err == nil {
+			return number
+		}
+	}
+
+	return value
+}
+
+func removeConfigNulls(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if item != nil {
+				m[key] = removeConfigNulls(item)
+			}
+		}
+		return m
+	case []interface{}:
+		items := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if item != nil {
+				items = append(items, removeConfigNulls(item))
+			}
+		}
+		return items
+	default:
+		return v
+	}
+}
+
+// decodeEnvFile parses the lines KEY=value of a .env file, where the nested keys are separated by __, as DB__HOST=localhost.
+// the values between quotes are strings, and the others are parsed as json when they can be, as MAX_WORKERS=5
+func decodeEnvFile(data []byte) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		text = strings.TrimPrefix(text, "export ")
+		index := strings.Index(text, "=")
+		if index < 1 {
+			return nil, fmt.Errorf("invalid line %d: expected KEY=value", line)
+		}
+
+		key, raw := strings.TrimSpace(text[:index]), strings.TrimSpace(text[index+1:])
+
+		var value interface{}
+		switch {
+		case len(raw) > 1 && raw[0] == '"' && raw[len(raw)-1] == '"':
+			unquoted, err := strconv.Unquote(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of %s on the line %d: %s", key, line, err)
+			}
+			value = unquoted
+		case len(raw) > 1 && raw[0] == '\'' && raw[len(raw)-1] == '\'':
+			value = raw[1 : len(raw)-1]
+		default:
+			value = parseConfigValue(nil, raw, nil)
+		}
+
+		current := values
+		path := strings.Split(strings.ToLower(key), envSeparator)
+		for _, name := range path[:len(path)-1] {
+			next, ok := current[name].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				current[name] = next
+			}
+			current = next
+		}
+		current[path[len(path)-1]] = value
+	}
+
+	return values, scanner.Err()
+}
+
+// encodeEnvFile writes the leaves of the values as lines KEY=value, with the strings between quotes
+// and the other values as json
+func encodeEnvFile(values map[string]interface{}) ([]byte, error) {
+	fields := make(map[string]interface{})
+	flattenConfig(values, "", fields)
+
+	var buffer bytes.Buffer
+	for _, key := range sortedKeys(fields) {
+		var value string
+		if text, ok := fields[key].(string); ok {
+			value = strconv.Quote(text)
+		} else {
+			data, err := json.Marshal(fields[key])
+			if err != nil {
+				return nil, err
+			}
+			value = string(data)
+		}
+
+		fmt.Fprintf(&buffer, "%s=%s\n", strings.ToUpper(strings.Replace(key, ".", envSeparator, -1)), value)
+	}
+
+	return buffer.Bytes(), nil
+}
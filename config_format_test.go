@@ -0,0 +1,92 @@
+package manager
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigFormats(t *testing.T) {
+	contents := map[string]string{
+		"app.yml":  "redis:\n  cache:\n    host: localhost\n    port: 6379\ndbs:\n  main:\n    driver: postgres\n    datasource: postgres://localhost/main\n",
+		"app.toml": "[redis.cache]\nhost = \"localhost\"\nport = 6379\n\n[dbs.main]\ndriver = \"postgres\"\ndatasource = \"postgres://localhost/main\"\n",
+		"app.env":  "# the cache\nREDIS__CACHE__HOST=localhost\nREDIS__CACHE__PORT=6379\nDBS__MAIN__DRIVER=\"postgres\"\nexport DBS__MAIN__DATASOURCE='postgres://localhost/main'\n",
+		"app.json": `{"redis": {"cache": {"host": "localhost", "port": 6379}}, "dbs": {"main": {"driver": "postgres", "datasource": "postgres://localhost/main"}}}`,
+	}
+
+	for name, content := range contents {
+		t.Run(name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), name)
+			if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := NewManager().NewSimpleConfig(file, &AppConfig{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			obj := config.GetObj().(*AppConfig)
+			if obj.Redis["cache"].Port != 6379 || obj.DBs["main"].DataSource != "postgres://localhost/main" || config.GetString("redis.cache.host") != "localhost" {
+				t.Fatalf("expected the config of the file, got %+v %+v", obj.Redis["cache"], obj.DBs["main"])
+			}
+
+			obj.Redis["cache"].Port = 6380
+			if err = config.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			saved, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if isJSON := json.Valid(saved); isJSON != (filepath.Ext(name) == ".json") {
+				t.Fatalf("expected the file to be saved on its format\n%s", saved)
+			}
+
+			reloaded := &AppConfig{}
+			if _, err = NewManager().NewSimpleConfig(file, reloaded); err != nil {
+				t.Fatal(err)
+			}
+			if reloaded.Redis["cache"].Port != 6380 || reloaded.Redis["cache"].Host != "localhost" || reloaded.DBs["main"].Driver != "postgres" {
+				t.Fatalf("expected the saved config to be read back, got %+v %+v\n%s", reloaded.Redis["cache"], reloaded.DBs["main"], saved)
+			}
+		})
+	}
+
+	t.Run("app.hcl", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "app.hcl")
+		content := "redis \"cache\" {\n  host = \"localhost\"\n  port = 6379\n}\n\ndbs \"main\" {\n  driver = \"postgres\"\n  datasource = \"postgres://localhost/main\"\n}\n"
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		config, err := NewManager().NewSimpleConfig(file, &AppConfig{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		obj := config.GetObj().(*AppConfig)
+		if obj.Redis["cache"].Port != 6379 || obj.DBs["main"].DataSource != "postgres://localhost/main" || config.GetString("redis.cache.host") != "localhost" {
+			t.Fatalf("expected the config of the file, got %+v %+v", obj.Redis["cache"], obj.DBs["main"])
+		}
+
+		// the hcl files are only read
+		if err = config.Save(); err == nil || !strings.Contains(err.Error(), "read only") {
+			t.Fatalf("expected an error saving the hcl file, got %v", err)
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "app.ini")
+		if err := ioutil.WriteFile(file, []byte("port = 1"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := NewManager().NewSimpleConfig(file, &AppConfig{}); err == nil || !strings.Contains(err.Error(), "unsupported") {
+			t.Fatalf("expected the unsupported format to be reported, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,375 @@
+package manager
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	SecretEnv  = "env"
+	SecretFile = "file"
+)
+
+// secretPattern matches the references to secrets on the values of a config, as ${env:DB_PASSWORD}
+var secretPattern = regexp.MustCompile(`\$\{([a-zA-Z][a-zA-Z0-9_-]*):([^}]+)\}`)
+
+// SecretProvider resolves the references to secrets of its scheme, as db_password on ${vault:db_password}
+type SecretProvider interface {
+	Resolve(ref string) (string, error)
+}
+
+// SecretProviderFunc ...
+type SecretProviderFunc func(ref string) (string, error)
+
+// Resolve ...
+func (f SecretProviderFunc) Resolve(ref string) (string, error) {
+	return f(ref)
+}
+
+// defaultSecretProviders resolves the ${env:NAME} references with the environment variables
+// and the ${file:/path} references with the content of the files, as the docker and kubernetes secrets
+func defaultSecretProviders() map[string]SecretProvider {
+	return map[string]SecretProvider{
+		SecretEnv: SecretProviderFunc(func(ref string) (string, error) {
+			value, exists := os.LookupEnv(ref)
+			if !exists {
+				return "", fmt.Errorf("the environment variable %s isn't set", ref)
+			}
+			return value, nil
+		}),
+		SecretFile: SecretProviderFunc(func(ref string) (string, error) {
+			data, err := ioutil.ReadFile(ref)
+			if err != nil {
+				return "", err
+			}
+			return strings.TrimRight(string(data), "\r\n"), nil
+		}),
+	}
+}
+
+// configSecret is a value of a config with references to secrets, kept so the references are saved instead of the secrets
+type configSecret struct {
+	path     []interface{}
+	raw      string
+	resolved string
+	refs     []secretRef
+}
+
+// secretRef is a reference to a secret on a value, as ${env:DB_PASSWORD}, with the secret it was resolved to
+type secretRef struct {
+	ref    string
+	secret string
+}
+
+// resolveSecrets replaces the references to secrets on the string values of the config file,
+// returning the values that were resolved
+func resolveSecrets(file string, values map[string]interface{}, providers map[string]SecretProvider) ([]*configSecret, error) {
+	var secrets []*configSecret
+	var errs []string
+	walkSecrets(values, nil, func(path []interface{}, raw string) string {
+		resolved, refs, err := resolveSecretRefs(raw, providers)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", configPathKey(path), err))
+			return raw
+		}
+
+		secrets = append(secrets, &configSecret{path: path, raw: raw, resolved: resolved, refs: refs})
+		return resolved
+	})
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return nil, fmt.Errorf("error resolving the secrets of the config %s [ %s ]", file, strings.Join(errs, ", "))
+	}
+
+	return secrets, nil
+}
+
+// resolveSecretRefs replaces every reference of the value, as the password on postgres://user:${env:DB_PASSWORD}@host/db
+func resolveSecretRefs(value string, providers map[string]SecretProvider) (string, []secretRef, error) {
+	var refs []secretRef
+	var err error
+	resolved := secretPattern.ReplaceAllStringFunc(value, func(match string) string {
+		if err != nil {
+			return match
+		}
+
+		groups := secretPattern.FindStringSubmatch(match)
+		provider, exists := providers[groups[1]]
+		if !exists {
+			err = fmt.Errorf("there isn't a secret provider for %s", groups[1])
+			return match
+		}
+
+		var secret string
+		if secret, err = provider.Resolve(groups[2]); err != nil {
+			// the reference is reported, never the secret
+			err = fmt.Errorf("error resolving the secret ${%s:%s}: %s", groups[1], groups[2], err)
+			return match
+		}
+
+		refs = append(refs, secretRef{ref: match, secret: secret})
+		return secret
+	})
+
+	return resolved, refs, err
+}
+
+// walkSecrets calls the resolve on each string value with references to secrets, replacing it with the result
+func walkSecrets(value interface{}, path []interface{}, resolve func(path []interface{}, raw string) string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = walkSecrets(item, appendPath(path, key), resolve)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = walkSecrets(item, appendPath(path, i), resolve)
+		}
+	case string:
+		if secretPattern.MatchString(v) {
+			return resolve(path, v)
+		}
+	}
+
+	return value
+}
+
+// restoreSecrets puts back the references on the values of the object with the resolved secrets,
+// so the secrets aren't written to the file, failing when a secret of a value was changed
+func restoreSecrets(obj interface{}, secrets []*configSecret) (interface{}, error) {
+	if len(secrets) == 0 {
+		return obj, nil
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var values interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+
+	for _, secret := range secrets {
+		if values, err = restoreSecret(values, secret.path, secret); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+func restoreSecret(value interface{}, path []interface{}, secret *configSecret) (interface{}, error) {
+	if len(path) == 0 {
+		text, ok := value.(string)
+		if !ok {
+			return value, nil
+		}
+
+		restored, err := secret.restore(text)
+		if err != nil {
+			return nil, err
+		}
+
+		return restored, nil
+	}
+
+	var err error
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if key, ok := path[0].(string); ok {
+			// the keys of the object can differ on the case from the ones of the file
+			key = findConfigKey(v, key)
+			if item, exists := v[key]; exists {
+				v[key], err = restoreSecret(item, path[1:], secret)
+			}
+		}
+	case []interface{}:
+		if i, ok := path[0].(int); ok && i < len(v) {
+			v[i], err = restoreSecret(v[i], path[1:], secret)
+		}
+	}
+
+	return value, err
+}
+
+// restore replaces the secrets of the value, that may have been edited around them, by their references.
+// it fails when a secret isn't on the value anymore, as it would be a changed secret written to the file
+func (secret *configSecret) restore(value string) (string, error) {
+	if value == secret.resolved {
+		return secret.raw, nil
+	}
+
+	// the longest secrets first, so a secret that contains another one is restored whole
+	refs := append([]secretRef(nil), secret.refs...)
+	sort.SliceStable(refs, func(i, j int) bool {
+		return len(refs[i].secret) > len(refs[j].secret)
+	})
+
+	for _, ref := range refs {
+		if ref.secret == "" || !strings.Contains(value, ref.secret) {
+			return "", fmt.Errorf("the secret %s of %s was changed, it can't be saved without writing it to the file", ref.ref, configPathKey(secret.path))
+		}
+
+		value = strings.ReplaceAll(value, ref.secret, ref.ref)
+	}
+
+	return value, nil
+}
+
+func appendPath(path []interface{}, item interface{}) []interface{} {
+	return append(append([]interface{}(nil), path...), item)
+}
+
+// configPathKey returns the key of the path, as dbs.main.datasource or nsq.lookupd[0]
+func configPathKey(path []interface{}) string {
+	var key string
+	for _, item := range path {
+		switch v := item.(type) {
+		case int:
+			key += fmt.Sprintf("[%d]", v)
+		default:
+			key = joinConfigKey(key, fmt.Sprint(v))
+		}
+	}
+
+	return key
+}
+
+// EncryptedFileSecretProvider resolves the secrets of a local json file of names and values encrypted with aes-gcm,
+// with the base64 key of 32 bytes of an environment variable
+type EncryptedFileSecretProvider struct {
+	file string
+	key  []byte
+	mux  sync.Mutex
+}
+
+// NewEncryptedFileSecretProvider ...
+func NewEncryptedFileSecretProvider(file, keyEnv string) (*EncryptedFileSecretProvider, error) {
+	encoded, exists := os.LookupEnv(keyEnv)
+	if !exists {
+		return nil, fmt.Errorf("the environment variable %s of the secrets key isn't set", keyEnv)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("the secrets key of %s isn't base64: %s", keyEnv, err)
+	}
+
+	if len(key) != 32 {
+		return nil, fmt.Errorf("the secrets key of %s should have 32 bytes, got %d", keyEnv, len(key))
+	}
+
+	return &EncryptedFileSecretProvider{file: file, key: key}, nil
+}
+
+// Resolve decrypts the secret with the name
+func (provider *EncryptedFileSecretProvider) Resolve(name string) (string, error) {
+	provider.mux.Lock()
+	defer provider.mux.Unlock()
+
+	secrets, err := provider.read()
+	if err != nil {
+		return "", err
+	}
+
+	encrypted, exists := secrets[name]
+	if !exists {
+		return "", fmt.Errorf("there isn't the secret %s on %s", name, provider.file)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", fmt.Errorf("the secret %s isn't base64: %s", name, err)
+	}
+
+	gcm, err := provider.cipher()
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("the secret %s is too short", name)
+	}
+
+	value, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("error decrypting the secret %s: %s", name, err)
+	}
+
+	return string(value), nil
+}
+
+// Set encrypts the secret with the name into the file, creating it when it doesn't exist
+func (provider *EncryptedFileSecretProvider) Set(name, value string) error {
+	provider.mux.Lock()
+	defer provider.mux.Unlock()
+
+	secrets, err := provider.read()
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if secrets == nil {
+		secrets = make(map[string]string)
+	}
+
+	gcm, err := provider.cipher()
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+
+	// the name is authenticated with the value, so the encrypted values can't be swapped between names
+	secrets[name] = base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(value), []byte(name)))
+
+	data, err := json.MarshalIndent(secrets, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(provider.file, data, 0600)
+}
+
+func (provider *EncryptedFileSecretProvider) read() (map[string]string, error) {
+	data, err := ioutil.ReadFile(provider.file)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make(map[string]string)
+	if err = json.Unmarshal(data, &secrets); err != nil {
+		return nil, fmt.Errorf("invalid secrets file %s: %s", provider.file, err)
+	}
+
+	return secrets, nil
+}
+
+func (provider *EncryptedFileSecretProvider) cipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(provider.key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
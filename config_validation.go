@@ -0,0 +1,314 @@
+package manager
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// validateTag is the tag of the rules of the fields of a config, as `validate:"required,min=1"`.
+// the rules are required, omitempty, min=n, max=n, oneof=a b c, url and duration, where min and max
+// are the length of the strings, slices and maps, and a duration as 1s on the time.Duration fields
+const validateTag = "validate"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// ConfigViolation is a rule of a config that isn't met by the value of the key
+type ConfigViolation struct {
+	Key     string `json:"key"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// String ...
+func (violation ConfigViolation) String() string {
+	return fmt.Sprintf("%s: %s", violation.Key, violation.Message)
+}
+
+// ConfigValidationError has every violation of a config
+type ConfigValidationError struct {
+	File       string            `json:"file,omitempty"`
+	Violations []ConfigViolation `json:"violations"`
+}
+
+// Error ...
+func (err *ConfigValidationError) Error() string {
+	violations := make([]string, len(err.Violations))
+	for i, violation := range err.Violations {
+		violations[i] = violation.String()
+	}
+
+	if err.File != "" {
+		return fmt.Sprintf("invalid config %s [ %s ]", err.File, strings.Join(violations, ", "))
+	}
+
+	return fmt.Sprintf("invalid config [ %s ]", strings.Join(violations, ", "))
+}
+
+// ValidateConfig checks the object against the validate tags of its fields,
+// returning a ConfigValidationError with every violation by the json key path
+func ValidateConfig(obj interface{}) error {
+	var violations []ConfigViolation
+	validateValue(reflect.ValueOf(obj), "", &violations)
+
+	return newConfigValidationError("", violations)
+}
+
+// ValidateConfigSchema checks the json of the config against the json schema of the file
+func ValidateConfigSchema(schemaFile string, data []byte) error {
+	schemaBytes, err := ReadFile(schemaFile, nil)
+	if err != nil {
+		return err
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schemaBytes), gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return fmt.Errorf("error validating the config with the schema %s: %s", schemaFile, err)
+	}
+
+	var violations []ConfigViolation
+	for _, resultError := range result.Errors() {
+		key := resultError.Field()
+		if key == gojsonschema.STRING_ROOT_SCHEMA_PROPERTY {
+			key = ""
+		}
+
+		// the required errors are reported on the parent, so the key is completed with the missing property
+		if property, ok := resultError.Details()["property"].(string); ok && resultError.Type() == "required" {
+			key = joinConfigKey(key, property)
+		}
+
+		violations = append(violations, ConfigViolation{Key: key, Rule: resultError.Type(), Message: resultError.Description()})
+	}
+
+	return newConfigValidationError("", violations)
+}
+
+// validateConfig checks the object with the tags of its fields and the json of the file with the json schema,
+// when there is one, returning every violation of both
+func validateConfig(file, schemaFile string, obj interface{}, data []byte) error {
+	var violations []ConfigViolation
+	validateValue(reflect.ValueOf(obj), "", &violations)
+
+	if schemaFile != "" {
+		if err := ValidateConfigSchema(schemaFile, data); err != nil {
+			validationErr, ok := err.(*ConfigValidationError)
+			if !ok {
+				return err
+			}
+			violations = append(violations, validationErr.Violations...)
+		}
+	}
+
+	return newConfigValidationError(file, violations)
+}
+
+func newConfigValidationError(file string, violations []ConfigViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Key < violations[j].Key })
+
+	return &ConfigValidationError{File: file, Violations: violations}
+}
+
+// validateValue walks the structs, pointers, slices and maps, validating the tagged fields
+func validateValue(value reflect.Value, key string, violations *[]ConfigViolation) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !value.IsNil() {
+			validateValue(value.Elem(), key, violations)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+
+			name, embedded := configFieldName(field)
+			if name == "-" {
+				continue
+			}
+
+			fieldKey := key
+			if !embedded {
+				fieldKey = joinConfigKey(key, name)
+			}
+
+			if rules, exists := field.Tag.Lookup(validateTag); exists {
+				validateRules(value.Field(i), fieldKey, rules, violations)
+			}
+			validateValue(value.Field(i), fieldKey, violations)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			validateValue(value.Index(i), fmt.Sprintf("%s[%d]", key, i), violations)
+		}
+	case reflect.Map:
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, mapKey := range keys {
+			validateValue(value.MapIndex(mapKey), joinConfigKey(key, fmt.Sprint(mapKey)), violations)
+		}
+	}
+}
+
+// validateRules checks the rules of the tag of a field
+func validateRules(value reflect.Value, key, rules string, violations *[]ConfigViolation) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			break
+		}
+		value = value.Elem()
+	}
+
+	empty := !value.IsValid() || value.IsZero() || (isLengthKind(value.Kind()) && value.Len() == 0)
+
+	for _, rule := range strings.Split(rules, ",") {
+		name, param := rule, ""
+		if i := strings.Index(rule, "="); i >= 0 {
+			name, param = rule[:i], rule[i+1:]
+		}
+
+		if name == "omitempty" {
+			if empty {
+				return
+			}
+			continue
+		}
+
+		if name == "required" {
+			if empty {
+				*violations = append(*violations, ConfigViolation{Key: key, Rule: name, Message: "is required"})
+				// the other rules would only repeat the missing value
+				return
+			}
+			continue
+		}
+
+		if !value.IsValid() || value.Kind() == reflect.Ptr {
+			continue
+		}
+
+		if message := checkRule(value, name, param); message != "" {
+			*violations = append(*violations, ConfigViolation{Key: key, Rule: name, Message: message})
+		}
+	}
+}
+
+// checkRule returns the message of the violation of the rule, or an empty one when the value meets it
+func checkRule(value reflect.Value, rule, param string) string {
+	switch rule {
+	case "min", "max":
+		limit, size, err := ruleLimit(value, param)
+		if err != nil {
+			return err.Error()
+		}
+
+		if rule == "min" && size < limit {
+			return fmt.Sprintf("should be at least %s", param)
+		}
+
+		if rule == "max" && size > limit {
+			return fmt.Sprintf("should be at most %s", param)
+		}
+	case "oneof":
+		text := fmt.Sprint(value.Interface())
+		for _, option := range strings.Fields(param) {
+			if text == option {
+				return ""
+			}
+		}
+
+		return fmt.Sprintf("should be one of [%s], got %q", param, text)
+	case "url":
+		if value.Kind() != reflect.String {
+			return "should be a string to be an url"
+		}
+
+		if parsed, err := url.Parse(value.String()); err != nil || parsed.Scheme == "" || (parsed.Host == "" && parsed.Opaque == "") {
+			return fmt.Sprintf("should be an url, got %q", value.String())
+		}
+	case "duration":
+		if value.Type() == durationType {
+			return ""
+		}
+
+		if value.Kind() != reflect.String {
+			return "should be a string to be a duration"
+		}
+
+		if _, err := time.ParseDuration(value.String()); err != nil {
+			return fmt.Sprintf("should be a duration as 1s or 500ms, got %q", value.String())
+		}
+	default:
+		return fmt.Sprintf("unknown validation rule %s", rule)
+	}
+
+	return ""
+}
+
+// ruleLimit returns the limit of the min and max rules and the size of the value to compare with it,
+// that is the length of the strings, slices and maps
+func ruleLimit(value reflect.Value, param string) (limit, size float64, err error) {
+	if value.Type() == durationType {
+		duration, err := time.ParseDuration(param)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid duration limit %s", param)
+		}
+
+		return float64(duration), float64(value.Int()), nil
+	}
+
+	if limit, err = strconv.ParseFloat(param, 64); err != nil {
+		return 0, 0, fmt.Errorf("invalid limit %s", param)
+	}
+
+	switch kind := value.Kind(); {
+	case isLengthKind(kind):
+		size = float64(value.Len())
+	case kind >= reflect.Int && kind <= reflect.Int64:
+		size = float64(value.Int())
+	case kind >= reflect.Uint && kind <= reflect.Uintptr:
+		size = float64(value.Uint())
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		size = value.Float()
+	default:
+		return 0, 0, fmt.Errorf("the value of kind %s can't have limits", kind)
+	}
+
+	return limit, size, nil
+}
+
+// configFieldName returns the json name of the field, and if it is embedded with its fields on the parent
+func configFieldName(field reflect.StructField) (string, bool) {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" {
+		if field.Anonymous {
+			return field.Name, true
+		}
+		name = field.Name
+	}
+
+	return name, false
+}
+
+func isLengthKind(kind reflect.Kind) bool {
+	return kind == reflect.String || kind == reflect.Slice || kind == reflect.Array || kind == reflect.Map
+}
+
+func joinConfigKey(key, name string) string {
+	if key == "" {
+		return name
+	}
+
+	return key + "." + name
+}
@@ -0,0 +1,82 @@
+package manager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigValidation(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.json")
+	write := func(content string) {
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(`{
+		"dbs": {"main": {"driver": "postgres", "data_source": "postgres://localhost/main"}},
+		"nsq": {"producers": {"events": {"topic": "events"}}},
+		"rabbitmq": {"consumers": {"orders": {"uri": "localhost", "exchange": "orders", "exchange_type": "direct", "queue": "orders"}}},
+		"webs": {"api": {"type": "grpc", "host": ":8080"}},
+		"worklists": {"jobs": {"max_workers": 0}}
+	}`)
+
+	_, err := NewManager().NewSimpleConfig(file, &AppConfig{})
+	validationErr, ok := err.(*ConfigValidationError)
+	if !ok {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+
+	var keys []string
+	for _, violation := range validationErr.Violations {
+		keys = append(keys, violation.Key+" "+violation.Rule)
+	}
+
+	expected := []string{
+		"dbs.main.datasource required",
+		"nsq.producers.events.lookupd required",
+		"rabbitmq.consumers.orders.uri url",
+		"webs.api.type oneof",
+		"worklists.jobs.max_workers min",
+	}
+	if fmt.Sprint(keys) != fmt.Sprint(expected) {
+		t.Fatalf("expected the violations %v, got %v", expected, keys)
+	}
+
+	schema := filepath.Join(dir, "schema.json")
+	if err := ioutil.WriteFile(schema, []byte(`{
+		"type": "object",
+		"required": ["manager"],
+		"properties": {"manager": {"type": "object", "properties": {"log": {"type": "object", "properties": {"level": {"enum": ["info", "debug"]}}}}}}
+	}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	write(`{"manager": {"log": {"level": "info"}}, "worklists": {"jobs": {"max_workers": 2}}}`)
+	config, err := NewManager().NewSimpleConfig(file, &AppConfig{}, WithConfigSchema(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write(`{"manager": {"log": {"level": "verbose"}}, "worklists": {"jobs": {"max_workers": 0}}}`)
+	err = config.Reload()
+	if validationErr, ok = err.(*ConfigValidationError); !ok || len(validationErr.Violations) != 2 {
+		t.Fatalf("expected the violations of the tags and the schema, got %v", err)
+	}
+	if validationErr.Violations[0].Key != "manager.log.level" || validationErr.Violations[1].Key != "worklists.jobs.max_workers" {
+		t.Fatalf("unexpected violations %v", validationErr.Violations)
+	}
+
+	if obj := config.GetObj().(*AppConfig); obj.Manager.Log.Level != "info" || obj.WorkLists["jobs"].MaxWorkers != 2 || config.GetInt("worklists.jobs.max_workers") != 2 {
+		t.Fatalf("expected the invalid reload to be refused, got %+v", obj)
+	}
+
+	write(`{"worklists": {"jobs": {"max_workers": 2}}}`)
+	if err = config.Reload(); err == nil || !strings.Contains(err.Error(), "manager") {
+		t.Fatalf("expected the missing manager to be reported, got %v", err)
+	}
+}
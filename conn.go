@@ -13,13 +13,52 @@ import (
 
 // Connect ...
 func (config *RedisConfig) Connect() (redis.Client, error) {
-	spec := redis.DefaultSpec().Host(config.Host).Port(config.Port).Password(config.Password).Db(config.Database)
-	return redis.NewSynchClientWithSpec(spec)
+	return redis.NewSynchClientWithSpec(config.spec())
 }
 
-// Connect ...
+// ConnectPubSub connects a client to subscribe to channels, as the subscribed connections can't run other commands
+func (config *RedisConfig) ConnectPubSub() (redis.PubSubClient, error) {
+	return redis.NewPubSubClientWithSpec(config.spec())
+}
+
+func (config *RedisConfig) spec() *redis.ConnectionSpec {
+	return redis.DefaultSpec().Host(config.Host).Port(config.Port).Password(config.Password).Db(config.Database)
+}
+
+// Connect opens the database with the pool settings, without connecting to it
 func (config *DBConfig) Connect() (*sql.DB, error) {
-	return sql.Open(config.Driver, config.DataSource)
+	connMaxLifetime, err := parseConfigDuration(config.ConnMaxLifetime, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid conn_max_lifetime: %s", err)
+	}
+
+	connMaxIdleTime, err := parseConfigDuration(config.ConnMaxIdleTime, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid conn_max_idle_time: %s", err)
+	}
+
+	db, err := sql.Open(config.Driver, config.DataSource)
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	if config.MaxIdleConns != 0 {
+		db.SetMaxIdleConns(config.MaxIdleConns)
+	}
+	db.SetConnMaxLifetime(connMaxLifetime)
+	db.SetConnMaxIdleTime(connMaxIdleTime)
+
+	return db, nil
+}
+
+// parseConfigDuration parses a duration of a config, as 30s, or returns the default when it is empty
+func parseConfigDuration(value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	return time.ParseDuration(value)
 }
 
 // Connect ...
@@ -42,3 +81,20 @@ func (config *RabbitmqConfig) Connect() (*amqp.Connection, error) {
 
 	return connection, nil
 }
+
+// checkRabbitmqConnection fails when the connection was closed, with the channel given to the connection NotifyClose
+func checkRabbitmqConnection(closed chan *amqp.Error) error {
+	if closed == nil {
+		return fmt.Errorf("rabbitmq not connected")
+	}
+
+	select {
+	case err, ok := <-closed:
+		if ok && err != nil {
+			return fmt.Errorf("rabbitmq connection closed: %s", err)
+		}
+		return fmt.Errorf("rabbitmq connection closed")
+	default:
+		return nil
+	}
+}
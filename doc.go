@@ -11,6 +11,29 @@ Web Servers
 Gateways
 Redis Connections
 Work Queues (with FIFO and LIFO modes)
+Dependencies between components (start and stop ordering)
+Context based lifecycle with start and stop deadlines
+Graceful shutdown, draining webs, consumers and work lists before stopping them
+Liveness and readiness health reports, with routes to mount on webs
+Prometheus metrics of queues, workers, consumers, producers, gateways, databases and web routes
+Adding, removing and replacing components while the manager is running
+Generic component registry, with typed getters and components of custom kinds
+Component states (registered, starting, running, degraded, stopping, stopped, failed) with event subscriptions
+Supervised processes with restart policies, exponential backoff, restart limits and escalation
+Scheduled processes with cron expressions (seconds and time zones) or intervals, jitter and overlap policies
+Leader election on redis, running singleton processes only on the leader replica
+Signal policy (graceful or immediate stop, config reload on SIGHUP, log level toggle, dumps on SIGUSR1) with custom handlers
+Admin HTTP/JSON API to list, start, stop, restart, pause and resume components, dump work lists, reload configs and change the log level
+Command line tool, at cmd/manager, to operate the services through the admin API
+Declarative bootstrap of the components from the AppConfig file with NewManagerFromConfig
+Layered configs (defaults, base file, app.{env}.json, prefixed environment variables and flags) with the source of each value
+Config file watching (file system notifications or polling) with debounced live reloads and change callbacks, per config or per key
+Config validation with validate tags (required, min, max, oneof, url, duration) and JSON schemas, reporting every violation by key and refusing invalid reloads
+Secrets on the config values with ${env:NAME}, ${file:/path} and pluggable providers, as a local AES encrypted file, that are never saved back
+Remote configs on redis (a JSON key or a hash per service and environment), with pub/sub change notifications, a local fallback file and write-through saves
+Typed config getters with defaults (GetIntOr), errors on missing keys (GetIntE), Has and Unmarshal of sub-trees, on .json, .yaml, .yml, .toml, .env and .hcl files
+Database pool settings (max open and idle connections, connection lifetime and idle time), a startup ping with retries and backoff, and the pool statistics on IDB.Stats
+Replicated databases with the writes on the primary and the reads balanced over the replicas (round-robin or least connections), with health-based ejection and fallback to the primary
 
 Usage
 at https://github.com/joaosoft/go-manager/tree/master/example
@@ -1,6 +1,7 @@
 package manager
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/joaosoft/web"
@@ -11,8 +12,6 @@ import (
 	"os"
 	"time"
 
-	"sync"
-
 	"github.com/labstack/echo"
 	"github.com/nsqio/go-nsq"
 )
@@ -135,7 +134,7 @@ func usage() {
 	}
 	simpleWeb = manager.GetWeb("web_echo")
 	simpleWeb.AddRoute(http.MethodGet, "/web_echo/:id", dummy_web_echo_handler)
-	go simpleWeb.Start(&sync.WaitGroup{}) // starting this because of the gateway
+	go simpleWeb.Start(context.Background()) // starting this because of the gateway
 
 	logger.Info("waiting 1 seconds...")
 	<-time.After(time.Duration(1) * time.Second)
@@ -178,11 +177,13 @@ func usage() {
 	workqueueConfig := NewWorkListConfig("queue_001", 1, 2, time.Second*2, FIFO)
 	workqueue := manager.NewSimpleWorkList(workqueueConfig, work_handler, nil, nil)
 	manager.AddWorkList("queue_001", workqueue)
+	// the work queue is only started after the postgres database
+	manager.AddDependency(KindWorkList, "queue_001", NewComponentId(KindDB, "postgres"))
 	workqueue = manager.GetWorkList("queue_001")
 	for i := 1; i <= 1000; i++ {
 		go workqueue.AddWork(fmt.Sprintf("PROCESS: %d", i), fmt.Sprintf("THIS IS MY MESSAGE %d", i))
 	}
-	if err := workqueue.Start(&sync.WaitGroup{}); err != nil {
+	if err := workqueue.Start(context.Background()); err != nil {
 		logger.Errorf("MAIN: error on workqueue %s", err)
 	}
 
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/rand"
@@ -146,7 +147,7 @@ func main() {
 	}
 	simpleWeb = m.GetWeb("web_echo")
 	simpleWeb.AddRoute(http.MethodGet, "/web_echo/:id", dummy_web_echo_handler)
-	go simpleWeb.Start() // starting this because of the gateway
+	go simpleWeb.Start(context.Background()) // starting this because of the gateway
 
 	log.Info("waiting 1 seconds...")
 	<-time.After(time.Duration(1) * time.Second)
@@ -193,7 +194,7 @@ func main() {
 	for i := 1; i <= 1000; i++ {
 		workqueue.AddWork(fmt.Sprintf("PROCESS: %d", i), fmt.Sprintf("THIS IS MY MESSAGE %d", i))
 	}
-	if err := workqueue.Start(); err != nil {
+	if err := workqueue.Start(context.Background()); err != nil {
 		log.Errorf("MAIN: error on workqueue %s", err)
 	}
 
@@ -206,7 +207,7 @@ func main() {
 	for i := 1; i <= 1000; i++ {
 		workqueue.AddWork(fmt.Sprintf("PROCESS: %d", i), fmt.Sprintf("THIS IS MY MESSAGE %d", i))
 	}
-	if err := workqueue.Start(); err != nil {
+	if err := workqueue.Start(context.Background()); err != nil {
 		log.Errorf("MAIN: error on bulk workqueue %s", err)
 	}
 
@@ -225,7 +226,7 @@ func main() {
 		log.Errorf("%s", err)
 	}
 
-	if err := rabbitmqProducer.Start(); err != nil {
+	if err := rabbitmqProducer.Start(context.Background()); err != nil {
 		log.Errorf("%s", err)
 	}
 	m.AddRabbitmqProducer("rabbitmq_producer", rabbitmqProducer)
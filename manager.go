@@ -1,36 +1,50 @@
 package manager
 
 import (
+	"context"
+	"io"
 	"os"
 	"os/signal"
-	"reflect"
-	"syscall"
 
 	"sync"
+	"time"
 
 	"github.com/joaosoft/logger"
 )
 
 // Manager ...
 type Manager struct {
-	processes         map[string]IProcess
-	configs           map[string]IConfig
-	redis             map[string]IRedis
-	nsqProducers      map[string]INSQProducer
-	nsqConsumers      map[string]INSQConsumer
-	rabbitmqProducers map[string]IRabbitmqProducer
-	rabbitmqConsumers map[string]IRabbitmqConsumer
-	dbs               map[string]IDB
-	webs              map[string]IWeb
-	gateways          map[string]IGateway
-	worklist          map[string]IWorkList
-	runInBackground   bool
-	config            *ManagerConfig
-	logger            logger.ILogger
-	isLogExternal     bool
-
-	quit    chan int
-	started bool
+	registry             *Registry[interface{}]
+	dependencies         map[ComponentId][]ComponentId
+	timeouts             map[ComponentId]*Timeouts
+	defaultTimeouts      *Timeouts
+	drainTimeout         time.Duration
+	healthTimeout        time.Duration
+	healthInterval       time.Duration
+	metrics              *Metrics
+	states               map[ComponentId]ComponentState
+	registrations        map[ComponentId]uint64
+	lastRegistration     uint64
+	eventHandlers        []func(event ComponentEvent)
+	signalPolicy         map[os.Signal]SignalAction
+	signalHandlers       map[os.Signal][]SignalHandler
+	backgroundSignals    bool
+	dumpWriter           io.Writer
+	runInBackground      bool
+	rollbackOnStartError bool
+	config               *ManagerConfig
+	logger               logger.ILogger
+	logLevel             logger.Level
+	debug                bool
+	isLogExternal        bool
+
+	ctx          context.Context
+	cancel       context.CancelFunc
+	quit         chan int
+	escalations  chan error
+	started      bool
+	mux          *sync.RWMutex
+	lifecycleMux *sync.Mutex
 }
 
 // NewManager ...
@@ -39,21 +53,27 @@ func NewManager(options ...ManagerOption) *Manager {
 	log := logger.NewLogDefault("manager", logger.WarnLevel)
 
 	service := &Manager{
-		processes:         make(map[string]IProcess),
-		configs:           make(map[string]IConfig),
-		redis:             make(map[string]IRedis),
-		nsqProducers:      make(map[string]INSQProducer),
-		nsqConsumers:      make(map[string]INSQConsumer),
-		rabbitmqProducers: make(map[string]IRabbitmqProducer),
-		rabbitmqConsumers: make(map[string]IRabbitmqConsumer),
-		dbs:               make(map[string]IDB),
-		webs:              make(map[string]IWeb),
-		gateways:          make(map[string]IGateway),
-		worklist:          make(map[string]IWorkList),
-		quit:              make(chan int),
-		logger:            log,
-		config:            config.Manager,
+		registry:        NewRegistry[interface{}](),
+		states:          make(map[ComponentId]ComponentState),
+		registrations:   make(map[ComponentId]uint64),
+		dependencies:    make(map[ComponentId][]ComponentId),
+		timeouts:        make(map[ComponentId]*Timeouts),
+		defaultTimeouts: &Timeouts{},
+		healthTimeout:   defaultHealthTimeout,
+		healthInterval:  defaultHealthInterval,
+		metrics:         NewMetrics(),
+		signalPolicy:    DefaultSignalPolicy(),
+		signalHandlers:  make(map[os.Signal][]SignalHandler),
+		dumpWriter:      os.Stderr,
+		quit:            make(chan int),
+		escalations:     make(chan error, 1),
+		mux:             &sync.RWMutex{},
+		lifecycleMux:    &sync.Mutex{},
+		logger:          log,
+		logLevel:        logger.WarnLevel,
+		config:          config.Manager,
 	}
+	service.ctx, service.cancel = context.WithCancel(context.Background())
 
 	if err != nil {
 		service.logger.Error(err.Error())
@@ -61,183 +81,350 @@ func NewManager(options ...ManagerOption) *Manager {
 		level, _ := logger.ParseLevel(config.Manager.Log.Level)
 		service.logger.Debugf("setting log level to %s", level)
 		service.logger.Reconfigure(logger.WithLevel(level))
+		service.logLevel = level
 	}
 
 	service.Reconfigure(options...)
+	service.metrics.AddCollector(service.collectDBStats)
 
 	return service
 }
 
 // Started ...
 func (manager *Manager) Started() bool {
+	manager.mux.RLock()
+	defer manager.mux.RUnlock()
+
 	return manager.started
 }
 
 // Start ...
 func (manager *Manager) Start() error {
-	if !manager.started {
-		manager.started = true
-		c := make(chan bool, 1)
-		if manager.runInBackground {
-			go manager.executeStart(c)
-			<-c
-		} else {
-			return manager.executeStart(c)
-		}
+	if manager.Started() {
+		return nil
+	}
+
+	manager.mux.Lock()
+	if manager.started {
+		manager.mux.Unlock()
+		return nil
+	}
+
+	// the context of a stop that is still draining is only canceled after the drain
+	if manager.cancel == nil || manager.ctx.Err() != nil {
+		manager.ctx, manager.cancel = context.WithCancel(context.Background())
+	}
+
+	manager.started = true
+	ctx := manager.ctx
+	healthInterval := manager.healthInterval
+	manager.mux.Unlock()
+
+	if healthInterval > 0 {
+		go manager.checkHealth(ctx, healthInterval)
+	}
+
+	// forget the escalations of the previous run
+	select {
+	case <-manager.escalations:
+	default:
 	}
 
-	return nil
+	c := make(chan error, 1)
+	if manager.runInBackground {
+		go manager.executeStart(ctx, c)
+		return <-c
+	} else {
+		return manager.executeStart(ctx, c)
+	}
 }
 
-// Stop ...
+// Stop drains and stops the components
 func (manager *Manager) Stop() error {
-	if manager.started {
-		manager.started = false
-		c := make(chan bool)
-		if manager.runInBackground {
-			go manager.executeStop(c)
-			<-c
-		} else {
-			return manager.executeStop(c)
-		}
+	return manager.stop(true)
+}
+
+// StopImmediately stops the components without draining them
+func (manager *Manager) StopImmediately() error {
+	return manager.stop(false)
+}
 
+func (manager *Manager) stop(drain bool) error {
+	manager.mux.Lock()
+	if !manager.started {
+		manager.mux.Unlock()
 		return nil
 	}
 
-	return nil
+	// the context is canceled after the drain, so the work in flight can finish,
+	// and the next start takes a new context
+	manager.started = false
+	cancel := manager.cancel
+	manager.cancel = nil
+	manager.mux.Unlock()
+
+	c := make(chan error, 1)
+	if manager.runInBackground {
+		go manager.executeStop(drain, cancel, c)
+		return <-c
+	} else {
+		return manager.executeStop(drain, cancel, c)
+	}
 }
 
-func (manager *Manager) executeStart(c chan bool) error {
+func (manager *Manager) executeStart(ctx context.Context, c chan error) error {
 	manager.logger.Info("starting...")
 
-	// listen for termination signals
-	termChan := make(chan os.Signal, 1)
+	// the components aren't added, removed or stopped while they are being started,
+	// so the order is planned with the components that are started
+	manager.lifecycleMux.Lock()
 
-	if !manager.runInBackground {
-		signal.Notify(termChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR1)
-	}
+	// the manager was stopped before its components could be started
+	if ctx.Err() != nil {
+		manager.lifecycleMux.Unlock()
 
-	var wg sync.WaitGroup
+		if manager.runInBackground {
+			c <- nil
+		}
 
-	if err := manager.executeAction("start", manager.dbs, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("start", manager.nsqProducers, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("start", manager.nsqConsumers, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("start", manager.rabbitmqProducers, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("start", manager.rabbitmqConsumers, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("start", manager.redis, &wg); err != nil {
-		return err
+		return nil
 	}
-	if err := manager.executeAction("start", manager.worklist, &wg); err != nil {
+
+	components := manager.lifecycleComponents()
+	batches, err := manager.startOrder(components)
+	if err != nil {
+		manager.logger.Error(err)
+		manager.cancelStart()
+		manager.lifecycleMux.Unlock()
+
+		if manager.runInBackground {
+			c <- err
+		}
+
 		return err
 	}
-	if err := manager.executeAction("start", manager.processes, &wg); err != nil {
-		return err
+
+	// listen for the signals of the signal policy
+	signalChan := make(chan os.Signal, 1)
+	defer signal.Stop(signalChan)
+
+	if !manager.runInBackground || manager.backgroundSignals {
+		signal.Notify(signalChan, manager.signals()...)
 	}
-	if err := manager.executeAction("start", manager.webs, &wg); err != nil {
-		return err
+
+	errs := make(ComponentErrors)
+	for _, batch := range batches {
+		// the next batches depend on this one, so they aren't started
+		if errs = manager.executeAction(ctx, "start", batch, components); len(errs) > 0 {
+			break
+		}
 	}
 
-	wg.Wait()
+	if len(errs) > 0 {
+		manager.logger.Errorf("error starting [ %s ]", errs)
+		signal.Stop(signalChan)
+
+		if manager.rollbackOnStartError {
+			manager.logger.Info("rolling back the started components...")
+			manager.cancelStart()
+
+			for _, batch := range manager.stopOrder(components) {
+				for id, err := range manager.executeAction(context.Background(), "stop", batch, components) {
+					errs[id] = err
+				}
+			}
+		}
+		manager.lifecycleMux.Unlock()
+
+		if manager.runInBackground {
+			c <- errs
+		}
+
+		return errs
+	}
+	manager.lifecycleMux.Unlock()
 
 	if manager.runInBackground {
-		c <- true
+		c <- nil
 	}
 
 	manager.logger.Infof("started")
 
-	select {
-	case <-termChan:
-		manager.logger.Infof("received term signal")
-	case <-manager.quit:
-		manager.logger.Infof("received shutdown signal")
+	for {
+		select {
+		case sig := <-signalChan:
+			switch manager.handleSignal(sig) {
+			case SignalGracefulStop:
+				return manager.Stop()
+			case SignalImmediateStop:
+				return manager.StopImmediately()
+			}
+		case <-manager.quit:
+			manager.logger.Infof("received shutdown signal")
+			return manager.Stop()
+		case err := <-manager.escalations:
+			manager.logger.Errorf("stopping on escalation: %s", err)
+			if errStop := manager.Stop(); errStop != nil {
+				manager.logger.Errorf("error stopping on escalation: %s", errStop)
+			}
+			return err
+		case <-ctx.Done():
+			// already stopped by a call to Stop
+			return nil
+		}
 	}
+}
 
-	return manager.Stop()
+// cancelStart marks the manager as stopped when its start is given up, canceling its context
+func (manager *Manager) cancelStart() {
+	manager.mux.Lock()
+	defer manager.mux.Unlock()
+
+	manager.started = false
+	if manager.cancel != nil {
+		manager.cancel()
+	}
 }
 
-func (manager *Manager) executeStop(c chan bool) error {
+func (manager *Manager) executeStop(drain bool, cancel context.CancelFunc, c chan error) error {
+	manager.lifecycleMux.Lock()
+	defer manager.lifecycleMux.Unlock()
+
 	manager.logger.Info("stopping...")
 
-	var wg sync.WaitGroup
+	components := manager.lifecycleComponents()
+	batches := manager.stopOrder(components)
+	errs := make(ComponentErrors)
 
-	if err := manager.executeAction("stop", manager.processes, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("stop", manager.worklist, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("stop", manager.webs, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("stop", manager.nsqProducers, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("stop", manager.nsqConsumers, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("stop", manager.rabbitmqProducers, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("stop", manager.rabbitmqConsumers, &wg); err != nil {
-		return err
-	}
-	if err := manager.executeAction("stop", manager.redis, &wg); err != nil {
-		return err
+	// let the components finish the work in flight before stopping them
+	if drain {
+		drainCtx, cancelDrain := withTimeout(context.Background(), manager.drainTimeout)
+		for _, batch := range batches {
+			for id, err := range manager.executeAction(drainCtx, "drain", batch, components) {
+				errs[id] = err
+			}
+		}
+		cancelDrain()
 	}
-	if err := manager.executeAction("stop", manager.dbs, &wg); err != nil {
-		return err
+	cancel()
+
+	for _, batch := range batches {
+		// stop everything, even when some components fail
+		for id, err := range manager.executeAction(context.Background(), "stop", batch, components) {
+			errs[id] = err
+		}
 	}
 
-	wg.Wait()
+	var err error
+	if len(errs) > 0 {
+		manager.logger.Errorf("error stopping [ %s ]", errs)
+		err = errs
+	} else {
+		manager.logger.Infof("stopped")
+	}
 
 	if manager.runInBackground {
-		c <- true
+		c <- err
 	}
 
-	manager.logger.Infof("stopped")
-
-	return nil
+	return err
 }
 
-func (manager *Manager) executeAction(action string, obj interface{}, wg *sync.WaitGroup) error {
-	wg.Add(1)
-	defer wg.Done()
+// executeAction executes the action on every component of the batch at the same time,
+// returning the errors of the components that failed
+func (manager *Manager) executeAction(ctx context.Context, action string, batch []ComponentId, components map[ComponentId]interface{}) ComponentErrors {
+	var wg sync.WaitGroup
+	var mux sync.Mutex
+	errs := make(ComponentErrors)
+
+	for _, id := range batch {
+		// the batches may have been planned before the component was removed
+		component, exists := components[id]
+		if !exists {
+			continue
+		}
+
+		lifecycle, err := lifecycleOf(component)
+		if err != nil {
+			errs.Add(id, action, err)
+			continue
+		}
+
+		started := lifecycle.Started()
+		if action == "start" && started {
+			manager.setState(id, StateRunning, nil)
+			continue
+		}
+
+		if action != "start" && !started {
+			continue
+		}
+
+		drainable, isDrainable := component.(IDrainable)
+		if action == "drain" && !isDrainable {
+			continue
+		}
 
-	objMap := reflect.ValueOf(obj)
+		wg.Add(1)
+		go func(id ComponentId, lifecycle ILifecycle) {
+			defer wg.Done()
 
-	if objMap.Kind() == reflect.Map {
-		var wgProcess sync.WaitGroup
-		for _, key := range objMap.MapKeys() {
-			value := objMap.MapIndex(key)
+			var err error
+			timeouts := manager.GetTimeouts(id.Kind, id.Key)
 
-			started := reflect.ValueOf(value.Interface()).MethodByName("Started").Call([]reflect.Value{})[0]
 			switch action {
 			case "start":
-				if !started.Bool() {
-					wgProcess.Add(1)
-					go reflect.ValueOf(value.Interface()).MethodByName("Start").Call([]reflect.Value{reflect.ValueOf(&wgProcess)})
-					manager.logger.Infof("started [ process: %s ]", key)
+				manager.setState(id, StateStarting, nil)
+
+				ctx, cancel := withTimeout(ctx, timeouts.Start)
+				defer cancel()
+
+				if err = lifecycle.Start(ctx); err != nil {
+					manager.setState(id, StateFailed, err)
+				} else {
+					manager.setState(id, StateRunning, nil)
+				}
+			case "drain":
+				manager.setState(id, StateStopping, nil)
+
+				ctx, cancel := withTimeout(ctx, timeouts.Drain)
+				defer cancel()
+
+				// the component is stopped next, even when it fails to drain
+				if err = drainable.Drain(ctx); err != nil {
+					manager.logger.Errorf("error on drain [ %s ]: %s", id, err)
 				}
 			case "stop":
-				if started.Bool() {
-					wgProcess.Add(1)
-					go reflect.ValueOf(value.Interface()).MethodByName("Stop").Call([]reflect.Value{reflect.ValueOf(&wgProcess)})
-					manager.logger.Infof("stopped [ process: %s ]", key)
+				manager.setState(id, StateStopping, nil)
+
+				ctx, cancel := withTimeout(ctx, timeouts.Stop)
+				defer cancel()
+
+				if err = lifecycle.Stop(ctx); err != nil {
+					manager.setState(id, StateFailed, err)
+				} else {
+					manager.setState(id, StateStopped, nil)
 				}
 			}
-		}
-		wgProcess.Wait()
+
+			if err != nil {
+				mux.Lock()
+				errs.Add(id, action, err)
+				mux.Unlock()
+			}
+		}(id, lifecycle)
 	}
+	wg.Wait()
 
-	return nil
+	return errs
+}
+
+// escalate stops the manager on an unrecoverable failure of a component,
+// which is returned by Start when it isn't running in background
+func (manager *Manager) escalate(err error) {
+	select {
+	case manager.escalations <- err:
+	default:
+		// the manager is already stopping on another escalation
+	}
 }
@@ -0,0 +1,256 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/joaosoft/logger"
+)
+
+// ComponentHandlers are the handlers bound in code to the components of the AppConfig, by key
+type ComponentHandlers struct {
+	NSQConsumers      map[string]INSQHandler
+	RabbitmqConsumers map[string]RabbitmqHandler
+	WorkLists         map[string]*WorkListHandlers
+	BulkWorkLists     map[string]*BulkWorkListHandlers
+}
+
+// WorkListHandlers ...
+type WorkListHandlers struct {
+	Handler              WorkHandler
+	Recover              WorkRecoverHandler
+	RecoverWastedRetries WorkRecoverWastedRetriesHandler
+}
+
+// BulkWorkListHandlers ...
+type BulkWorkListHandlers struct {
+	Handler              BulkWorkHandler
+	Recover              BulkWorkRecoverHandler
+	RecoverWastedRetries BulkWorkRecoverWastedRetriesHandler
+}
+
+// NewManagerFromConfig creates a manager with the components of the config, binding the handlers by key.
+// the config is loaded with NewConfig when it isn't given
+func NewManagerFromConfig(config *AppConfig, handlers *ComponentHandlers, options ...ManagerOption) (*Manager, error) {
+	if config == nil {
+		var err error
+		if config, _, err = NewConfig(); err != nil {
+			return nil, err
+		}
+	}
+
+	if handlers == nil {
+		handlers = &ComponentHandlers{}
+	}
+
+	manager := NewManager()
+	if config.Manager != nil && config.Manager.Log.Level != "" {
+		level, err := logger.ParseLevel(config.Manager.Log.Level)
+		if err != nil {
+			return nil, err
+		}
+		manager.SetLogLevel(level)
+	}
+	manager.Reconfigure(options...)
+
+	if err := manager.addFromConfig(config, handlers); err != nil {
+		return nil, err
+	}
+
+	return manager, nil
+}
+
+// addFromConfig creates and adds the components of the config
+func (manager *Manager) addFromConfig(config *AppConfig, handlers *ComponentHandlers) error {
+	for _, key := range sortedKeys(config.DBs) {
+		if err := manager.AddDB(key, manager.NewSimpleDB(config.DBs[key])); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range sortedKeys(config.Redis) {
+		if err := manager.AddRedis(key, manager.NewSimpleRedis(config.Redis[key])); err != nil {
+			return err
+		}
+	}
+
+	if config.NSQ != nil {
+		for _, key := range sortedKeys(config.NSQ.Producers) {
+			producer, err := manager.NewSimpleNSQProducer(config.NSQ.Producers[key])
+			if err != nil {
+				return fmt.Errorf("error creating the nsq producer %s: %s", key, err)
+			}
+
+			if err = manager.AddNSQProducer(key, producer); err != nil {
+				return err
+			}
+		}
+
+		for _, key := range sortedKeys(config.NSQ.Consumers) {
+			handler, exists := handlers.NSQConsumers[key]
+			if !exists {
+				return fmt.Errorf("there isn't a handler bound to the nsq consumer %s", key)
+			}
+
+			consumer, err := manager.NewSimpleNSQConsumer(config.NSQ.Consumers[key], handler)
+			if err != nil {
+				return fmt.Errorf("error creating the nsq consumer %s: %s", key, err)
+			}
+
+			if err = manager.AddNSQConsumer(key, consumer); err != nil {
+				return err
+			}
+		}
+	}
+
+	if config.Rabbitmq != nil {
+		for _, key := range sortedKeys(config.Rabbitmq.Producers) {
+			producer, err := manager.NewSimpleRabbitmqProducer(config.Rabbitmq.Producers[key])
+			if err != nil {
+				return fmt.Errorf("error creating the rabbitmq producer %s: %s", key, err)
+			}
+
+			if err = manager.AddRabbitmqProducer(key, producer); err != nil {
+				return err
+			}
+		}
+
+		for _, key := range sortedKeys(config.Rabbitmq.Consumers) {
+			handler, exists := handlers.RabbitmqConsumers[key]
+			if !exists {
+				return fmt.Errorf("there isn't a handler bound to the rabbitmq consumer %s", key)
+			}
+
+			consumerConfig := config.Rabbitmq.Consumers[key]
+			consumer, err := manager.NewSimpleRabbitmqConsumer(&consumerConfig.RabbitmqConfig, consumerConfig.Queue, consumerConfig.BindingKey, consumerConfig.Tag, handler)
+			if err != nil {
+				return fmt.Errorf("error creating the rabbitmq consumer %s: %s", key, err)
+			}
+
+			if err = manager.AddRabbitmqConsumer(key, consumer); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(config.Webs) {
+		web, err := manager.newWeb(config.Webs[key])
+		if err != nil {
+			return fmt.Errorf("error creating the web %s: %s", key, err)
+		}
+
+		if err = manager.AddWeb(key, web); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range sortedKeys(config.WorkLists) {
+		workListHandlers, exists := handlers.WorkLists[key]
+		if !exists || workListHandlers.Handler == nil {
+			return fmt.Errorf("there isn't a handler bound to the work list %s", key)
+		}
+
+		workListConfig := config.WorkLists[key]
+		if workListConfig.Name == "" {
+			workListConfig.Name = key
+		}
+
+		worklist := manager.NewSimpleWorkList(workListConfig, workListHandlers.Handler, workListHandlers.Recover, workListHandlers.RecoverWastedRetries)
+		if err := manager.AddWorkList(key, worklist); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range sortedKeys(config.BulkWorkLists) {
+		bulkWorkListHandlers, exists := handlers.BulkWorkLists[key]
+		if !exists || bulkWorkListHandlers.Handler == nil {
+			return fmt.Errorf("there isn't a handler bound to the bulk work list %s", key)
+		}
+
+		bulkWorkListConfig := config.BulkWorkLists[key]
+		if bulkWorkListConfig.Name == "" {
+			bulkWorkListConfig.Name = key
+		}
+
+		bulkWorklist := manager.NewSimpleBulkWorkList(bulkWorkListConfig, bulkWorkListHandlers.Handler, bulkWorkListHandlers.Recover, bulkWorkListHandlers.RecoverWastedRetries)
+		if err := manager.AddWorkList(key, bulkWorklist); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range sortedKeys(config.Gateways) {
+		gateway, err := manager.NewSimpleGateway()
+		if err != nil {
+			return fmt.Errorf("error creating the gateway %s: %s", key, err)
+		}
+
+		if err = manager.AddGateway(key, gateway); err != nil {
+			return err
+		}
+	}
+
+	return checkBoundHandlers(config, handlers)
+}
+
+// newWeb creates the web of the type of the config
+func (manager *Manager) newWeb(config *WebConfig) (IWeb, error) {
+	switch config.Type {
+	case WebTypeServer, "":
+		return manager.NewSimpleWebServer(config.Host), nil
+	case WebTypeEcho:
+		return manager.NewSimpleWebEcho(config.Host), nil
+	case WebTypeHttp:
+		return manager.NewSimpleWebHttp(config.Host), nil
+	default:
+		return nil, fmt.Errorf("invalid web type %s", config.Type)
+	}
+}
+
+// checkBoundHandlers fails for the handlers bound to components that aren't on the config, as a misspelled key
+func checkBoundHandlers(config *AppConfig, handlers *ComponentHandlers) error {
+	nsqConsumers := map[string]*NSQConfig{}
+	if config.NSQ != nil {
+		nsqConsumers = config.NSQ.Consumers
+	}
+
+	rabbitmqConsumers := map[string]*RabbitmqConsumerConfig{}
+	if config.Rabbitmq != nil {
+		rabbitmqConsumers = config.Rabbitmq.Consumers
+	}
+
+	for _, key := range sortedKeys(handlers.NSQConsumers) {
+		if _, exists := nsqConsumers[key]; !exists {
+			return fmt.Errorf("the handler is bound to the nsq consumer %s that isn't configured", key)
+		}
+	}
+
+	for _, key := range sortedKeys(handlers.RabbitmqConsumers) {
+		if _, exists := rabbitmqConsumers[key]; !exists {
+			return fmt.Errorf("the handler is bound to the rabbitmq consumer %s that isn't configured", key)
+		}
+	}
+
+	for _, key := range sortedKeys(handlers.WorkLists) {
+		if _, exists := config.WorkLists[key]; !exists {
+			return fmt.Errorf("the handler is bound to the work list %s that isn't configured", key)
+		}
+	}
+
+	for _, key := range sortedKeys(handlers.BulkWorkLists) {
+		if _, exists := config.BulkWorkLists[key]; !exists {
+			return fmt.Errorf("the handler is bound to the bulk work list %s that isn't configured", key)
+		}
+	}
+
+	return nil
+}
+
+func sortedKeys[T any](items map[string]T) []string {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestNewManagerFromConfig(t *testing.T) {
+	var config AppConfig
+	if err := json.Unmarshal([]byte(`{
+		"manager": {"log": {"level": "info"}},
+		"dbs": {"main": {"driver": "postgres", "datasource": "postgres://localhost/main"}},
+		"redis": {"cache": {"host": "localhost", "port": 6379}},
+		"webs": {"api": {"type": "http", "host": ":0"}},
+		"worklists": {"jobs": {"max_workers": 1, "max_retries": 1}},
+		"gateways": {"partner": {}}
+	}`), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	handlers := &ComponentHandlers{
+		WorkLists: map[string]*WorkListHandlers{
+			"jobs": {Handler: func(id string, data interface{}) error { return nil }},
+		},
+	}
+
+	manager, err := NewManagerFromConfig(&config, handlers, WithRunInBackground(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, info := range manager.Components() {
+		ids = append(ids, NewComponentId(info.Kind, info.Key).String())
+	}
+
+	expected := []string{"db: main", "redis: cache", "worklist: jobs", "web: api", "gateway: partner"}
+	if fmt.Sprint(ids) != fmt.Sprint(expected) {
+		t.Fatalf("expected the components %v, got %v", expected, ids)
+	}
+
+	if _, err := NewManagerFromConfig(&config, &ComponentHandlers{}); err == nil {
+		t.Fatal("expected an error for the work list without a handler")
+	}
+
+	handlers.WorkLists["misspelled"] = &WorkListHandlers{Handler: func(id string, data interface{}) error { return nil }}
+	if _, err := NewManagerFromConfig(&config, handlers); err == nil {
+		t.Fatal("expected an error for the handler bound to a work list that isn't configured")
+	}
+}
@@ -0,0 +1,364 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Register adds a component with a lifecycle under any kind and key, as the Add methods of the known kinds do,
+// with the components it depends on, starting it after them when the manager is started
+func (manager *Manager) Register(kind Kind, key string, component interface{}, dependencies ...ComponentId) error {
+	id := NewComponentId(kind, key)
+	if err := validateDependencies(id, dependencies); err != nil {
+		return err
+	}
+
+	if !canBeKind(kind, component) {
+		return fmt.Errorf("the component %T can't be of the kind of [ %s ]", component, id)
+	}
+
+	if err := manager.add(kind, key, component, dependencies...); err != nil {
+		return err
+	}
+
+	manager.logger.Infof("component %s added", id)
+
+	return nil
+}
+
+// Unregister removes the component with the given kind and key, stopping it gracefully when the manager is started,
+// and failing while other started components depend on it or when it fails to stop
+func (manager *Manager) Unregister(kind Kind, key string) (interface{}, error) {
+	component, err := manager.remove(kind, key)
+	manager.logger.Infof("component %s removed", NewComponentId(kind, key))
+
+	return component, err
+}
+
+// add registers the component with its dependencies, starting it when the manager is started,
+// failing when a component with the same kind and key already exists
+func (manager *Manager) add(kind Kind, key string, component interface{}, dependencies ...ComponentId) error {
+	if !isPassiveKind(kind) {
+		if _, err := lifecycleOf(component); err != nil {
+			return err
+		}
+	}
+
+	manager.lifecycleMux.Lock()
+	defer manager.lifecycleMux.Unlock()
+
+	id := NewComponentId(kind, key)
+	if !manager.registry.Add(id, component) {
+		return fmt.Errorf("the component [ %s ] already exists, it should be replaced", id)
+	}
+
+	// the dependencies are declared before the start, so the component is only started after them
+	previous := manager.GetDependencies(kind, key)
+	if len(dependencies) > 0 {
+		manager.mux.Lock()
+		manager.dependencies[id] = append(append([]ComponentId(nil), previous...), dependencies...)
+		manager.mux.Unlock()
+	}
+
+	manager.setState(id, StateRegistered, nil)
+	manager.attach(id, component)
+
+	if !manager.Started() || isPassiveKind(kind) {
+		return nil
+	}
+
+	ctx := manager.Context()
+	if err := manager.startComponent(ctx, id, component); err != nil {
+		// the manager stopped while the component was starting, it's started on the next start of the manager
+		if ctx.Err() != nil {
+			manager.setState(id, StateStopped, nil)
+			return nil
+		}
+
+		manager.registry.Remove(id)
+		manager.removeState(id)
+		if len(dependencies) > 0 {
+			manager.mux.Lock()
+			manager.dependencies[id] = previous
+			manager.mux.Unlock()
+		}
+		return err
+	}
+
+	return nil
+}
+
+// remove unregisters the component, stopping it gracefully when the manager is started,
+// where the component is kept registered when it can't be stopped
+func (manager *Manager) remove(kind Kind, key string) (interface{}, error) {
+	manager.lifecycleMux.Lock()
+	defer manager.lifecycleMux.Unlock()
+
+	id := NewComponentId(kind, key)
+	component, exists := manager.registry.Get(id)
+	if !exists {
+		return nil, nil
+	}
+
+	// the component is kept while other started components depend on it or while its stop fails
+	if manager.Started() && !isPassiveKind(kind) {
+		if err := manager.stopComponent(id, component); err != nil {
+			return component, err
+		}
+	}
+
+	manager.registry.Remove(id)
+	manager.removeState(id)
+
+	return component, nil
+}
+
+// Replace swaps the component with the given key by a new component of the same kind,
+// where the kind is the one of the registered component that the new component can replace
+func (manager *Manager) Replace(key string, component interface{}) error {
+	if component == nil {
+		return fmt.Errorf("the component %s can't be replaced by nil", key)
+	}
+
+	var candidates []Kind
+	for _, id := range manager.registry.Ids() {
+		if id.Key == key && canBeKind(id.Kind, component) {
+			candidates = append(candidates, id.Kind)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return fmt.Errorf("there isn't a component %s that can be replaced by %T", key, component)
+	case 1:
+		return manager.ReplaceComponent(candidates[0], key, component)
+	default:
+		sort.Slice(candidates, func(i, j int) bool {
+			return kindRank(candidates[i]) < kindRank(candidates[j])
+		})
+		return fmt.Errorf("the component %s is ambiguous between the kinds %v, it should be replaced with ReplaceComponent", key, candidates)
+	}
+}
+
+// ReplaceComponent swaps the component with the given kind and key by a new one.
+// when the manager is started, the previous component is stopped gracefully before the new one is started,
+// and it's started again when the new one fails to start
+func (manager *Manager) ReplaceComponent(kind Kind, key string, component interface{}) error {
+	id := NewComponentId(kind, key)
+	if id.Kind == "" {
+		return fmt.Errorf("invalid component kind [ %s ]", id)
+	}
+
+	if !isPassiveKind(kind) {
+		if _, err := lifecycleOf(component); err != nil {
+			return err
+		}
+	}
+
+	if !canBeKind(kind, component) {
+		return fmt.Errorf("the component %T can't replace [ %s ]", component, id)
+	}
+
+	manager.lifecycleMux.Lock()
+	defer manager.lifecycleMux.Unlock()
+
+	previous, exists := manager.registry.Get(id)
+	if !exists {
+		return fmt.Errorf("the component [ %s ] doesn't exist", id)
+	}
+
+	started := manager.Started() && !isPassiveKind(kind)
+	if started {
+		if err := manager.stopComponent(id, previous); err != nil {
+			return err
+		}
+	}
+
+	manager.registry.Set(id, component)
+	manager.setState(id, StateRegistered, nil)
+	manager.attach(id, component)
+
+	if started {
+		if err := manager.startComponent(manager.Context(), id, component); err != nil {
+			manager.registry.Set(id, previous)
+			manager.setState(id, StateRegistered, nil)
+			manager.attach(id, previous)
+
+			if errPrevious := manager.startComponent(manager.Context(), id, previous); errPrevious != nil {
+				manager.logger.Errorf("error restarting the replaced component [ %s ]: %s", id, errPrevious)
+			}
+
+			return err
+		}
+	}
+
+	manager.logger.Infof("component %s replaced", id)
+
+	return nil
+}
+
+// StartComponent starts the component with the given kind and key on a started manager
+func (manager *Manager) StartComponent(kind Kind, key string) error {
+	manager.lifecycleMux.Lock()
+	defer manager.lifecycleMux.Unlock()
+
+	id, component, err := manager.controllableComponent(kind, key)
+	if err != nil {
+		return err
+	}
+
+	return manager.startComponent(manager.Context(), id, component)
+}
+
+// StopComponent drains and stops the component with the given kind and key on a started manager,
+// where it's started again with the manager, failing while other started components depend on it
+func (manager *Manager) StopComponent(kind Kind, key string) error {
+	manager.lifecycleMux.Lock()
+	defer manager.lifecycleMux.Unlock()
+
+	id, component, err := manager.controllableComponent(kind, key)
+	if err != nil {
+		return err
+	}
+
+	return manager.stopComponent(id, component)
+}
+
+// RestartComponent stops and starts the component with the given kind and key on a started manager
+func (manager *Manager) RestartComponent(kind Kind, key string) error {
+	manager.lifecycleMux.Lock()
+	defer manager.lifecycleMux.Unlock()
+
+	id, component, err := manager.controllableComponent(kind, key)
+	if err != nil {
+		return err
+	}
+
+	if err = manager.stopComponent(id, component); err != nil {
+		return err
+	}
+
+	return manager.startComponent(manager.Context(), id, component)
+}
+
+// PauseComponent stops the component with the given kind and key from taking new work, when it's pausable
+func (manager *Manager) PauseComponent(kind Kind, key string) error {
+	pausable, err := manager.pausableComponent(kind, key)
+	if err != nil {
+		return err
+	}
+
+	if err = pausable.Pause(); err != nil {
+		return err
+	}
+
+	manager.logger.Infof("component %s paused", NewComponentId(kind, key))
+
+	return nil
+}
+
+// ResumeComponent resumes taking new work on the component with the given kind and key, when it's pausable
+func (manager *Manager) ResumeComponent(kind Kind, key string) error {
+	pausable, err := manager.pausableComponent(kind, key)
+	if err != nil {
+		return err
+	}
+
+	if err = pausable.Resume(); err != nil {
+		return err
+	}
+
+	manager.logger.Infof("component %s resumed", NewComponentId(kind, key))
+
+	return nil
+}
+
+// controllableComponent returns the component with a lifecycle to start or stop on a started manager
+func (manager *Manager) controllableComponent(kind Kind, key string) (ComponentId, interface{}, error) {
+	id := NewComponentId(kind, key)
+	if err := validateKind(id); err != nil {
+		return id, nil, err
+	}
+
+	component, exists := manager.registry.Get(id)
+	if !exists {
+		return id, nil, fmt.Errorf("the component [ %s ] doesn't exist", id)
+	}
+
+	if !manager.Started() {
+		return id, nil, fmt.Errorf("the manager isn't started")
+	}
+
+	return id, component, nil
+}
+
+func (manager *Manager) pausableComponent(kind Kind, key string) (IPausable, error) {
+	id := NewComponentId(kind, key)
+
+	component, exists := manager.registry.Get(id)
+	if !exists {
+		return nil, fmt.Errorf("the component [ %s ] doesn't exist", id)
+	}
+
+	pausable, ok := component.(IPausable)
+	if !ok {
+		return nil, fmt.Errorf("the component [ %s ] can't be paused", id)
+	}
+
+	return pausable, nil
+}
+
+// startComponent starts a component on a started manager, when its dependencies are started
+func (manager *Manager) startComponent(ctx context.Context, id ComponentId, component interface{}) error {
+	components := manager.lifecycleComponents()
+
+	for _, dependency := range manager.GetDependencies(id.Kind, id.Key) {
+		dependencyComponent, exists := components[dependency]
+		if !exists {
+			return fmt.Errorf("the component [ %s ] depends on the missing component [ %s ]", id, dependency)
+		}
+
+		if lifecycle, err := lifecycleOf(dependencyComponent); err != nil || !lifecycle.Started() {
+			return fmt.Errorf("the component [ %s ] depends on the stopped component [ %s ]", id, dependency)
+		}
+	}
+
+	errs := manager.executeAction(ctx, "start", []ComponentId{id}, map[ComponentId]interface{}{id: component})
+	return errs.Get(id.Kind, id.Key)
+}
+
+// stopComponent drains and stops a component of a started manager, failing when started components depend on it
+func (manager *Manager) stopComponent(id ComponentId, component interface{}) error {
+	if err := manager.checkDependents(id); err != nil {
+		return err
+	}
+
+	components := map[ComponentId]interface{}{id: component}
+
+	drainCtx, cancel := withTimeout(context.Background(), manager.drainTimeout)
+	defer cancel()
+
+	errs := manager.executeAction(drainCtx, "drain", []ComponentId{id}, components)
+	for errId, err := range manager.executeAction(context.Background(), "stop", []ComponentId{id}, components) {
+		errs[errId] = err
+	}
+
+	return errs.Get(id.Kind, id.Key)
+}
+
+// checkDependents fails when started components depend on the component, as they would run without it
+func (manager *Manager) checkDependents(id ComponentId) error {
+	dependents := manager.startedDependents(id)
+	if len(dependents) == 0 {
+		return nil
+	}
+
+	names := make([]string, len(dependents))
+	for i, dependent := range dependents {
+		names[i] = fmt.Sprintf("[ %s ]", dependent)
+	}
+
+	return fmt.Errorf("the component [ %s ] can't be stopped while the started components %s depend on it", id, strings.Join(names, ", "))
+}
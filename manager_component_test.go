@@ -0,0 +1,229 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStopWithStartedDependents(t *testing.T) {
+	manager := newTestManager()
+
+	base := manager.NewSimpleProcess(func() error { return nil })
+	dependent := manager.NewSimpleProcess(func() error { return nil })
+	if err := manager.AddProcess("base", base); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.AddProcess("dependent", dependent); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.AddDependency(KindProcess, "dependent", NewComponentId(KindProcess, "base")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Stop()
+
+	if _, err := manager.RemoveProcess("base"); err == nil || !strings.Contains(err.Error(), "[ process: dependent ]") {
+		t.Fatalf("expected the remove to fail naming the dependent, got %v", err)
+	}
+	if err := manager.StopComponent(KindProcess, "base"); err == nil {
+		t.Fatal("expected the stop to fail while the dependent is started")
+	}
+	if err := manager.ReplaceComponent(KindProcess, "base", manager.NewSimpleProcess(func() error { return nil })); err == nil {
+		t.Fatal("expected the replace to fail while the dependent is started")
+	}
+
+	if got := Get[IProcess](manager, "base"); got != base || !base.Started() {
+		t.Fatal("expected the base process to be kept and started")
+	}
+
+	// once the dependent is stopped, the component can be removed
+	if err := manager.StopComponent(KindProcess, "dependent"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.RemoveProcess("base"); err != nil {
+		t.Fatal(err)
+	}
+	if base.Started() {
+		t.Fatal("expected the base process to be stopped")
+	}
+}
+
+func TestConcurrentRegistration(t *testing.T) {
+	manager := newTestManager()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("process_%d", i%5)
+			process := manager.NewSimpleProcess(func() error { return nil })
+
+			if err := manager.AddProcess(key, process); err != nil {
+				t.Error(err)
+			}
+			manager.GetProcess(key)
+
+			if err := manager.AddDependency(KindProcess, key, NewComponentId(KindDB, "db")); err != nil {
+				t.Error(err)
+			}
+			manager.GetDependencies(KindProcess, key)
+			manager.RemoveDependencies(KindProcess, key)
+
+			if err := manager.SetTimeouts(KindProcess, key, NewTimeouts(time.Second, 0, time.Second)); err != nil {
+				t.Error(err)
+			}
+			manager.GetTimeouts(KindProcess, key)
+
+			manager.lifecycleComponents()
+			if _, err := manager.RemoveProcess(key); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestHotAddRemoveReplace(t *testing.T) {
+	manager := newTestManager()
+	calls := &testCalls{}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Stop()
+
+	// the components added to a started manager are started
+	first := &testComponent{name: "first", calls: calls}
+	if err := manager.Register("store", "cache", first); err != nil {
+		t.Fatal(err)
+	}
+	if !first.Started() || manager.State("store", "cache") != StateRunning {
+		t.Fatal("expected the added component to be started")
+	}
+	if err := manager.Register("store", "cache", &testComponent{name: "duplicated", calls: calls}); err == nil {
+		t.Fatal("expected an error adding a component that already exists")
+	}
+
+	failing := &testComponent{name: "failing", calls: calls, startErr: fmt.Errorf("failed")}
+	if err := manager.Register("store", "failing", failing); err == nil {
+		t.Fatal("expected an error adding a component that fails to start")
+	}
+	if _, exists := GetComponent[*testComponent](manager, "store", "failing"); exists || manager.State("store", "failing") != "" {
+		t.Fatal("expected the component that failed to start not to be kept")
+	}
+
+	if err := manager.Register("store", "dependent", &testComponent{name: "dependent", calls: calls}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.Unregister("store", "dependent"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.AddDependency("store", "dependent", NewComponentId("store", "missing")); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Register("store", "dependent", &testComponent{name: "dependent", calls: calls}); err == nil || !strings.Contains(err.Error(), "missing component") {
+		t.Fatalf("expected an error adding a component without its dependencies, got %v", err)
+	}
+
+	// the replaced component is stopped before the new one is started
+	second := &testComponent{name: "second", calls: calls}
+	if err := manager.Replace("cache", second); err != nil {
+		t.Fatal(err)
+	}
+	if first.Started() || !second.Started() {
+		t.Fatal("expected the new component to replace the previous one")
+	}
+	if got, _ := GetComponent[*testComponent](manager, "store", "cache"); got != second {
+		t.Fatalf("expected the new component to be registered, got %v", got)
+	}
+
+	// the previous component is kept when the new one fails to start
+	if err := manager.Replace("cache", &testComponent{name: "broken", calls: calls, startErr: fmt.Errorf("failed")}); err == nil {
+		t.Fatal("expected an error replacing by a component that fails to start")
+	}
+	if got, _ := GetComponent[*testComponent](manager, "store", "cache"); got != second || !second.Started() {
+		t.Fatal("expected the previous component to be restarted")
+	}
+
+	if err := manager.Replace("missing", second); err == nil {
+		t.Fatal("expected an error replacing a missing component")
+	}
+	if err := manager.Register("queue", "cache", &testComponent{name: "queue", calls: calls}); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Replace("cache", &testComponent{name: "ambiguous", calls: calls}); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Fatalf("expected an error replacing a key of several kinds, got %v", err)
+	}
+
+	// the removed components are stopped
+	if component, err := manager.Unregister("store", "cache"); err != nil || component != second {
+		t.Fatalf("expected the component to be removed, got %v %v", component, err)
+	}
+	if second.Started() || manager.State("store", "cache") != "" {
+		t.Fatal("expected the removed component to be stopped and forgotten")
+	}
+
+	// the component that fails to stop is kept registered
+	stuck := &testComponent{name: "stuck", calls: calls, stopErr: fmt.Errorf("failed")}
+	if err := manager.Register("store", "stuck", stuck); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.Unregister("store", "stuck"); err == nil {
+		t.Fatal("expected an error removing a component that fails to stop")
+	}
+	if got, _ := GetComponent[*testComponent](manager, "store", "stuck"); got != stuck || manager.State("store", "stuck") != StateFailed {
+		t.Fatal("expected the component that failed to stop to be kept")
+	}
+
+	expected := []string{"start first", "start failing", "start dependent", "stop dependent", "stop first", "start second",
+		"stop second", "start broken", "start second", "start queue", "stop second",
+		"start stuck", "stop stuck"}
+	if got := calls.get(); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Fatalf("expected the calls %v, got %v", expected, got)
+	}
+}
+
+func TestRegisterWithDependencies(t *testing.T) {
+	manager := newTestManager()
+	calls := &testCalls{}
+
+	if err := manager.Register("store", "cache", &testComponent{name: "cache", calls: calls}); err != nil {
+		t.Fatal(err)
+	}
+	// the duplicated components fail the same way on a stopped and a started manager
+	if err := manager.Register("store", "cache", &testComponent{name: "duplicated", calls: calls}); err == nil {
+		t.Fatal("expected an error adding a component that already exists on a stopped manager")
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Stop()
+
+	// the dependencies given on the registration are checked before the component is started
+	reader := &testComponent{name: "reader", calls: calls}
+	if err := manager.Register("store", "reader", reader, NewComponentId("store", "missing")); err == nil || !strings.Contains(err.Error(), "missing component") {
+		t.Fatalf("expected an error adding a component without its dependencies, got %v", err)
+	}
+	if reader.Started() || len(manager.GetDependencies("store", "reader")) != 0 {
+		t.Fatal("expected the dependencies of the component that failed to start not to be kept")
+	}
+
+	if err := manager.Register("store", "reader", reader, NewComponentId("store", "cache")); err != nil {
+		t.Fatal(err)
+	}
+	if !reader.Started() || fmt.Sprint(manager.GetDependencies("store", "reader")) != fmt.Sprint([]ComponentId{NewComponentId("store", "cache")}) {
+		t.Fatal("expected the component to be started with its dependencies")
+	}
+	if _, err := manager.Unregister("store", "cache"); err == nil {
+		t.Fatal("expected an error removing a component the started reader depends on")
+	}
+}
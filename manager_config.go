@@ -23,9 +23,60 @@ type IConfig interface {
 	Reload() error
 }
 
+// ITypedConfig is implemented by the configs with typed getters, that tell the missing keys
+// and the values of other types apart from the zero values, as the SimpleConfig
+type ITypedConfig interface {
+	IConfig
+
+	GetStringOr(key string, defaultValue string) string
+	GetBoolOr(key string, defaultValue bool) bool
+	GetIntOr(key string, defaultValue int) int
+	GetInt64Or(key string, defaultValue int64) int64
+	GetFloat64Or(key string, defaultValue float64) float64
+	GetTimeOr(key string, defaultValue time.Time) time.Time
+	GetDurationOr(key string, defaultValue time.Duration) time.Duration
+	GetStringSliceOr(key string, defaultValue []string) []string
+	GetStringMapOr(key string, defaultValue map[string]interface{}) map[string]interface{}
+	GetStringMapStringOr(key string, defaultValue map[string]string) map[string]string
+	GetStringMapStringSliceOr(key string, defaultValue map[string][]string) map[string][]string
+
+	GetStringE(key string) (string, error)
+	GetBoolE(key string) (bool, error)
+	GetIntE(key string) (int, error)
+	GetInt64E(key string) (int64, error)
+	GetFloat64E(key string) (float64, error)
+	GetTimeE(key string) (time.Time, error)
+	GetDurationE(key string) (time.Duration, error)
+	GetStringSliceE(key string) ([]string, error)
+	GetStringMapE(key string) (map[string]interface{}, error)
+	GetStringMapStringE(key string) (map[string]string, error)
+	GetStringMapStringSliceE(key string) (map[string][]string, error)
+
+	Has(key string) bool
+	Unmarshal(key string, target interface{}) error
+}
+
+// ConfigChangeHandler is called with the previous and the new object of a config after it is reloaded
+type ConfigChangeHandler func(oldObj, newObj interface{})
+
+// ConfigKeyChangeHandler is called with the previous and the new value of a key of a config that changed on a reload
+type ConfigKeyChangeHandler func(key string, oldValue, newValue interface{})
+
+// IWatchableConfig is implemented by the configs that can be reloaded when their source changes
+type IWatchableConfig interface {
+	IConfig
+	OnChange(handler ConfigChangeHandler)
+	OnKeyChange(key string, handler ConfigKeyChangeHandler)
+	Watch() error
+	Unwatch()
+}
+
 // AddConfig ...
 func (manager *Manager) AddConfig(key string, config IConfig) error {
-	manager.configs[key] = config
+	if err := manager.add(KindConfig, key, config); err != nil {
+		return err
+	}
+
 	manager.logger.Infof("config %s added", key)
 
 	return nil
@@ -33,17 +84,17 @@ func (manager *Manager) AddConfig(key string, config IConfig) error {
 
 // RemoveConfig ...
 func (manager *Manager) RemoveConfig(key string) (IConfig, error) {
-	config := manager.configs[key]
+	component, err := manager.remove(KindConfig, key)
+	config, _ := component.(IConfig)
 
-	delete(manager.configs, key)
 	manager.logger.Infof("config %s removed", key)
 
-	return config, nil
+	return config, err
 }
 
 // GetConfig ...
 func (manager *Manager) GetConfig(key string) IConfig {
-	if config, exists := manager.configs[key]; exists {
+	if config, exists := GetComponent[IConfig](manager, KindConfig, key); exists {
 		return config
 	}
 	manager.logger.Infof("config %s doesn't exist", key)
@@ -1,21 +1,40 @@
 package manager
 
 import (
+	"context"
 	"database/sql"
-	"sync"
 )
 
 type IDB interface {
 	Get() *sql.DB
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
 	Started() bool
+	Stats() sql.DBStats
+	Ping(ctx context.Context) error
 }
 
-// DBConfig ...
+// IReplicatedDB is a database with a primary for the writes and replicas for the reads
+type IReplicatedDB interface {
+	IDB
+	Writer() *sql.DB
+	Reader() *sql.DB
+}
+
+// DBConfig has the connection and the pool settings of a database, where the durations are as 30s or 5m.
+// the zero values keep the defaults of database/sql, and a negative max idle connections keeps none.
+// the database is pinged on start, with the ping retries waiting for the ping backoff, doubled on each retry,
+// where the ping backoff and the ping timeout must be positive
 type DBConfig struct {
-	Driver     string `json:"driver"`
-	DataSource string `json:"datasource"`
+	Driver          string `json:"driver" validate:"required"`
+	DataSource      string `json:"datasource" validate:"required"`
+	MaxOpenConns    int    `json:"max_open_conns" validate:"min=0"`
+	MaxIdleConns    int    `json:"max_idle_conns"`
+	ConnMaxLifetime string `json:"conn_max_lifetime" validate:"omitempty,duration"`
+	ConnMaxIdleTime string `json:"conn_max_idle_time" validate:"omitempty,duration"`
+	PingRetries     int    `json:"ping_retries" validate:"min=0"`
+	PingBackoff     string `json:"ping_backoff" validate:"omitempty,duration"`
+	PingTimeout     string `json:"ping_timeout" validate:"omitempty,duration"`
 }
 
 // NewDBConfig...
@@ -28,7 +47,10 @@ func NewDBConfig(driver, datasource string) *DBConfig {
 
 // AddDB ...
 func (manager *Manager) AddDB(key string, db IDB) error {
-	manager.dbs[key] = db
+	if err := manager.add(KindDB, key, db); err != nil {
+		return err
+	}
+
 	manager.logger.Infof("database %s added", key)
 
 	return nil
@@ -36,17 +58,17 @@ func (manager *Manager) AddDB(key string, db IDB) error {
 
 // RemoveDB ...
 func (manager *Manager) RemoveDB(key string) (IDB, error) {
-	db := manager.dbs[key]
+	component, err := manager.remove(KindDB, key)
+	db, _ := component.(IDB)
 
-	delete(manager.dbs, key)
 	manager.logger.Infof("database %s removed", key)
 
-	return db, nil
+	return db, err
 }
 
 // GetDB ...
 func (manager *Manager) GetDB(key string) IDB {
-	if db, exists := manager.dbs[key]; exists {
+	if db, exists := GetComponent[IDB](manager, KindDB, key); exists {
 		return db
 	}
 	manager.logger.Infof("database %s doesn't exist", key)
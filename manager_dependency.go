@@ -0,0 +1,323 @@
+package manager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Kind ...
+type Kind string
+
+const (
+	KindDB               Kind = "db"
+	KindNSQProducer      Kind = "nsq_producer"
+	KindNSQConsumer      Kind = "nsq_consumer"
+	KindRabbitmqProducer Kind = "rabbitmq_producer"
+	KindRabbitmqConsumer Kind = "rabbitmq_consumer"
+	KindRedis            Kind = "redis"
+	KindWorkList         Kind = "worklist"
+	KindProcess          Kind = "process"
+	KindWeb              Kind = "web"
+
+	// the components of these kinds don't have a lifecycle
+	KindConfig  Kind = "config"
+	KindGateway Kind = "gateway"
+)
+
+// kinds is the order used to start components that don't depend on each other,
+// where the components of custom kinds are started last
+var kinds = []Kind{
+	KindDB,
+	KindNSQProducer,
+	KindNSQConsumer,
+	KindRabbitmqProducer,
+	KindRabbitmqConsumer,
+	KindRedis,
+	KindWorkList,
+	KindProcess,
+	KindWeb,
+}
+
+// ComponentId ...
+type ComponentId struct {
+	Kind Kind
+	Key  string
+}
+
+// NewComponentId ...
+func NewComponentId(kind Kind, key string) ComponentId {
+	return ComponentId{
+		Kind: kind,
+		Key:  key,
+	}
+}
+
+// String ...
+func (id ComponentId) String() string {
+	return fmt.Sprintf("%s: %s", id.Kind, id.Key)
+}
+
+// AddDependency declares that the component with the given kind and key can only be started
+// after the given components are started, and must be stopped before they are stopped
+func (manager *Manager) AddDependency(kind Kind, key string, dependencies ...ComponentId) error {
+	id := NewComponentId(kind, key)
+	if err := validateDependencies(id, dependencies); err != nil {
+		return err
+	}
+
+	manager.mux.Lock()
+	manager.dependencies[id] = append(manager.dependencies[id], dependencies...)
+	manager.mux.Unlock()
+
+	manager.logger.Infof("dependencies of %s added", id)
+
+	return nil
+}
+
+// RemoveDependencies ...
+func (manager *Manager) RemoveDependencies(kind Kind, key string) []ComponentId {
+	id := NewComponentId(kind, key)
+
+	manager.mux.Lock()
+	dependencies := manager.dependencies[id]
+	delete(manager.dependencies, id)
+	manager.mux.Unlock()
+
+	manager.logger.Infof("dependencies of %s removed", id)
+
+	return dependencies
+}
+
+// GetDependencies ...
+func (manager *Manager) GetDependencies(kind Kind, key string) []ComponentId {
+	manager.mux.RLock()
+	defer manager.mux.RUnlock()
+
+	return manager.dependencies[NewComponentId(kind, key)]
+}
+
+// startedDependents returns the started components that depend on the given component
+func (manager *Manager) startedDependents(id ComponentId) []ComponentId {
+	components := manager.lifecycleComponents()
+
+	manager.mux.RLock()
+	defer manager.mux.RUnlock()
+
+	var dependents []ComponentId
+	for dependent, dependencies := range manager.dependencies {
+		component, exists := components[dependent]
+		if !exists {
+			continue
+		}
+
+		for _, dependency := range dependencies {
+			if dependency != id {
+				continue
+			}
+
+			if lifecycle, err := lifecycleOf(component); err == nil && lifecycle.Started() {
+				dependents = append(dependents, dependent)
+			}
+			break
+		}
+	}
+	sortComponentIds(dependents)
+
+	return dependents
+}
+
+// lifecycleComponents returns every registered component with a lifecycle
+func (manager *Manager) lifecycleComponents() map[ComponentId]interface{} {
+	components := manager.registry.Items()
+	for id := range components {
+		if isPassiveKind(id.Kind) {
+			delete(components, id)
+		}
+	}
+
+	return components
+}
+
+// startOrder sorts the components in batches, where each batch only depends on the previous ones.
+// it fails when a dependency is missing or when there is a dependency cycle
+func (manager *Manager) startOrder(components map[ComponentId]interface{}) ([][]ComponentId, error) {
+	return manager.sortComponents(components, true)
+}
+
+// stopOrder is the reverse of the start order, ignoring missing dependencies and cycles,
+// so everything that is registered gets stopped
+func (manager *Manager) stopOrder(components map[ComponentId]interface{}) [][]ComponentId {
+	batches, _ := manager.sortComponents(components, false)
+
+	for i, j := 0, len(batches)-1; i < j; i, j = i+1, j-1 {
+		batches[i], batches[j] = batches[j], batches[i]
+	}
+
+	return batches
+}
+
+func (manager *Manager) sortComponents(components map[ComponentId]interface{}, strict bool) ([][]ComponentId, error) {
+	manager.mux.RLock()
+	defer manager.mux.RUnlock()
+
+	pending := make(map[ComponentId][]ComponentId)
+
+	for id := range components {
+		pending[id] = make([]ComponentId, 0)
+
+		for _, dependency := range manager.dependencies[id] {
+			if _, exists := components[dependency]; !exists {
+				if strict {
+					return nil, fmt.Errorf("the component [ %s ] depends on the missing component [ %s ]", id, dependency)
+				}
+				continue
+			}
+			pending[id] = append(pending[id], dependency)
+		}
+	}
+
+	var batches [][]ComponentId
+	done := make(map[ComponentId]bool)
+
+	for len(pending) > 0 {
+		var ready []ComponentId
+		for id, dependencies := range pending {
+			isReady := true
+			for _, dependency := range dependencies {
+				if !done[dependency] {
+					isReady = false
+					break
+				}
+			}
+
+			if isReady {
+				ready = append(ready, id)
+			}
+		}
+
+		if len(ready) == 0 {
+			if strict {
+				return nil, fmt.Errorf("dependency cycle between components [ %s ]", findCycle(pending, done))
+			}
+
+			for id := range pending {
+				ready = append(ready, id)
+			}
+		}
+
+		// from the ready components, start only the ones of the first kind
+		rank := len(kinds)
+		for _, id := range ready {
+			if r := kindRank(id.Kind); r < rank {
+				rank = r
+			}
+		}
+
+		var batch []ComponentId
+		for _, id := range ready {
+			if kindRank(id.Kind) == rank {
+				batch = append(batch, id)
+			}
+		}
+
+		sortComponentIds(batch)
+
+		for _, id := range batch {
+			done[id] = true
+			delete(pending, id)
+		}
+
+		batches = append(batches, batch)
+	}
+
+	return batches, nil
+}
+
+// findCycle follows the pending dependencies until a component is repeated
+func findCycle(pending map[ComponentId][]ComponentId, done map[ComponentId]bool) string {
+	var ids []ComponentId
+	for id := range pending {
+		ids = append(ids, id)
+	}
+	sortComponentIds(ids)
+
+	visited := make(map[ComponentId]int)
+	var path []ComponentId
+
+	id := ids[0]
+	for {
+		if index, exists := visited[id]; exists {
+			path = append(path[index:], id)
+			break
+		}
+
+		visited[id] = len(path)
+		path = append(path, id)
+
+		for _, dependency := range pending[id] {
+			if !done[dependency] {
+				id = dependency
+				break
+			}
+		}
+	}
+
+	names := make([]string, len(path))
+	for i, id := range path {
+		names[i] = id.String()
+	}
+
+	return strings.Join(names, " -> ")
+}
+
+func kindRank(kind Kind) int {
+	for i, k := range kinds {
+		if k == kind {
+			return i
+		}
+	}
+
+	return len(kinds)
+}
+
+// validateKind fails for the kinds of the components without a lifecycle, where any other kind is valid
+func validateDependencies(id ComponentId, dependencies []ComponentId) error {
+	for _, dependency := range append([]ComponentId{id}, dependencies...) {
+		if err := validateKind(dependency); err != nil {
+			return err
+		}
+	}
+
+	for _, dependency := range dependencies {
+		if dependency == id {
+			return fmt.Errorf("the component [ %s ] can't depend on itself", id)
+		}
+	}
+
+	return nil
+}
+
+func validateKind(id ComponentId) error {
+	if id.Kind == "" || isPassiveKind(id.Kind) {
+		return fmt.Errorf("invalid component kind [ %s ]", id)
+	}
+
+	return nil
+}
+
+func isPassiveKind(kind Kind) bool {
+	return kind == KindConfig || kind == KindGateway
+}
+
+func sortComponentIds(ids []ComponentId) {
+	sort.Slice(ids, func(i, j int) bool {
+		if ids[i].Kind != ids[j].Kind {
+			if rankI, rankJ := kindRank(ids[i].Kind), kindRank(ids[j].Kind); rankI != rankJ {
+				return rankI < rankJ
+			}
+			return ids[i].Kind < ids[j].Kind
+		}
+		return ids[i].Key < ids[j].Key
+	})
+}
@@ -0,0 +1,149 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testCalls records the lifecycle calls of the test components, in the order they were made
+type testCalls struct {
+	calls []string
+	mux   sync.Mutex
+}
+
+func (calls *testCalls) add(call string) {
+	calls.mux.Lock()
+	defer calls.mux.Unlock()
+
+	calls.calls = append(calls.calls, call)
+}
+
+func (calls *testCalls) get() []string {
+	calls.mux.Lock()
+	defer calls.mux.Unlock()
+
+	return append([]string(nil), calls.calls...)
+}
+
+// testComponent is a component that records its lifecycle calls,
+// failing them with the given errors or blocking them until their context is done
+type testComponent struct {
+	name       string
+	calls      *testCalls
+	startErr   error
+	stopErr    error
+	blockStart bool
+	blockStop  bool
+	state      lifecycleState
+}
+
+func (component *testComponent) Start(ctx context.Context) error {
+	component.state.lock()
+	defer component.state.unlock()
+
+	component.calls.add("start " + component.name)
+
+	if component.blockStart {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	if component.startErr != nil {
+		return component.startErr
+	}
+
+	component.state.setStarted(true)
+	return nil
+}
+
+func (component *testComponent) Stop(ctx context.Context) error {
+	component.state.lock()
+	defer component.state.unlock()
+
+	component.calls.add("stop " + component.name)
+
+	if component.blockStop {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	component.state.setStarted(false)
+	return component.stopErr
+}
+
+func (component *testComponent) Started() bool {
+	return component.state.isStarted()
+}
+
+func TestDependencyOrder(t *testing.T) {
+	manager := newTestManager()
+	calls := &testCalls{}
+
+	// the worker depends on a component of a custom kind, that would be started after the processes otherwise
+	for _, id := range []ComponentId{NewComponentId(KindProcess, "worker"), NewComponentId(KindProcess, "ticker"), NewComponentId("store", "cache")} {
+		if err := manager.Register(id.Kind, id.Key, &testComponent{name: id.Key, calls: calls}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := manager.AddDependency(KindProcess, "worker", NewComponentId("store", "cache")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"start ticker", "start cache", "start worker", "stop worker", "stop cache", "stop ticker"}
+	if got := calls.get(); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Fatalf("expected the calls %v, got %v", expected, got)
+	}
+}
+
+func TestDependencyErrors(t *testing.T) {
+	manager := newTestManager()
+	calls := &testCalls{}
+
+	for _, id := range []ComponentId{NewComponentId(KindProcess, "worker"), NewComponentId(KindProcess, "ticker"), NewComponentId("store", "cache")} {
+		if err := manager.Register(id.Kind, id.Key, &testComponent{name: id.Key, calls: calls}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := manager.AddDependency(KindProcess, "worker", NewComponentId(KindProcess, "worker")); err == nil {
+		t.Fatal("expected an error for the component depending on itself")
+	}
+	if err := manager.AddDependency(KindProcess, "worker", NewComponentId(KindConfig, "app")); err == nil {
+		t.Fatal("expected an error for the dependency on a component without a lifecycle")
+	}
+
+	if err := manager.AddDependency(KindProcess, "worker", NewComponentId("store", "missing")); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Start(); err == nil || !strings.Contains(err.Error(), "depends on the missing component [ store: missing ]") {
+		t.Fatalf("expected the missing dependency to be reported, got %v", err)
+	}
+
+	manager.RemoveDependencies(KindProcess, "worker")
+	if err := manager.AddDependency(KindProcess, "worker", NewComponentId("store", "cache")); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.AddDependency("store", "cache", NewComponentId(KindProcess, "worker")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := manager.Start()
+	if err == nil || !strings.Contains(err.Error(), "dependency cycle between components [ process: worker -> store: cache -> process: worker ]") {
+		t.Fatalf("expected the cycle to be reported, got %v", err)
+	}
+
+	// nothing is started when the order can't be planned
+	if manager.Started() || len(calls.get()) != 0 {
+		t.Fatalf("expected nothing to be started, got %v", calls.get())
+	}
+}
@@ -0,0 +1,83 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ComponentError ...
+type ComponentError struct {
+	Id     ComponentId
+	Action string
+	Err    error
+}
+
+// NewComponentError ...
+func NewComponentError(id ComponentId, action string, err error) *ComponentError {
+	return &ComponentError{
+		Id:     id,
+		Action: action,
+		Err:    err,
+	}
+}
+
+// Error ...
+func (e *ComponentError) Error() string {
+	return fmt.Sprintf("error on %s of [ %s ]: %s", e.Action, e.Id, e.Err)
+}
+
+// Unwrap ...
+func (e *ComponentError) Unwrap() error {
+	return e.Err
+}
+
+// ComponentErrors are the errors of the components, by kind and key
+type ComponentErrors map[ComponentId]*ComponentError
+
+// Add ...
+func (errs ComponentErrors) Add(id ComponentId, action string, err error) {
+	errs[id] = NewComponentError(id, action, err)
+}
+
+// Get ...
+func (errs ComponentErrors) Get(kind Kind, key string) error {
+	if err, exists := errs[NewComponentId(kind, key)]; exists {
+		return err
+	}
+	return nil
+}
+
+// Error ...
+func (errs ComponentErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs.sorted() {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap ...
+func (errs ComponentErrors) Unwrap() []error {
+	unwrapped := make([]error, 0, len(errs))
+	for _, err := range errs.sorted() {
+		unwrapped = append(unwrapped, err)
+	}
+
+	return unwrapped
+}
+
+func (errs ComponentErrors) sorted() []*ComponentError {
+	ids := make([]ComponentId, 0, len(errs))
+	for id := range errs {
+		ids = append(ids, id)
+	}
+	sortComponentIds(ids)
+
+	sorted := make([]*ComponentError, 0, len(errs))
+	for _, id := range ids {
+		sorted = append(sorted, errs[id])
+	}
+
+	return sorted
+}
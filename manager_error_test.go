@@ -0,0 +1,95 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestStartErrors(t *testing.T) {
+	for _, rollback := range []bool{false, true} {
+		t.Run(fmt.Sprintf("rollback %t", rollback), func(t *testing.T) {
+			manager := NewManager(WithRunInBackground(true), WithRollbackOnStartError(rollback))
+			calls := &testCalls{}
+
+			ticker := &testComponent{name: "ticker", calls: calls}
+			errA, errB := fmt.Errorf("a failed"), fmt.Errorf("b failed")
+			components := map[ComponentId]*testComponent{
+				NewComponentId(KindProcess, "ticker"): ticker,
+				NewComponentId("store", "a"):          {name: "a", calls: calls, startErr: errA},
+				NewComponentId("store", "b"):          {name: "b", calls: calls, startErr: errB},
+			}
+			for id, component := range components {
+				if err := manager.Register(id.Kind, id.Key, component); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := manager.Start()
+			errs, ok := err.(ComponentErrors)
+			if !ok || len(errs) != 2 {
+				t.Fatalf("expected the errors of both components, got %v", err)
+			}
+
+			expected := "error on start of [ store: a ]: a failed; error on start of [ store: b ]: b failed"
+			if err.Error() != expected {
+				t.Fatalf("expected the error %q, got %q", expected, err.Error())
+			}
+			if !errors.Is(errs.Get("store", "a"), errA) || !errors.Is(err, errB) || errs.Get(KindProcess, "ticker") != nil {
+				t.Fatalf("expected the errors to wrap the ones of the components, got %v", err)
+			}
+
+			if rollback {
+				if manager.Started() || ticker.Started() {
+					t.Fatal("expected the started components to be rolled back")
+				}
+				if state := manager.State(KindProcess, "ticker"); state != StateStopped {
+					t.Fatalf("expected the ticker to be stopped, got %s", state)
+				}
+				return
+			}
+
+			if !manager.Started() || !ticker.Started() {
+				t.Fatal("expected the started components to be kept without the rollback")
+			}
+			if err := manager.Stop(); err != nil {
+				t.Fatal(err)
+			}
+			if ticker.Started() {
+				t.Fatal("expected the ticker to be stopped")
+			}
+		})
+	}
+}
+
+func TestStopErrors(t *testing.T) {
+	manager := newTestManager()
+	calls := &testCalls{}
+
+	ticker := &testComponent{name: "ticker", calls: calls}
+	errCache := fmt.Errorf("cache failed")
+	if err := manager.Register(KindProcess, "ticker", ticker); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Register("store", "cache", &testComponent{name: "cache", calls: calls, stopErr: errCache}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the other components are stopped, even when one of them fails
+	err := manager.Stop()
+	errs, ok := err.(ComponentErrors)
+	if !ok || len(errs) != 1 || !errors.Is(errs.Get("store", "cache"), errCache) {
+		t.Fatalf("expected the error of the cache, got %v", err)
+	}
+
+	if manager.Started() || ticker.Started() {
+		t.Fatal("expected the manager and the ticker to be stopped")
+	}
+	if state := manager.State("store", "cache"); state != StateFailed {
+		t.Fatalf("expected the cache to be failed, got %s", state)
+	}
+}
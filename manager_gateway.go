@@ -7,7 +7,10 @@ type IGateway interface {
 
 // AddGateway ...
 func (manager *Manager) AddGateway(key string, gateway IGateway) error {
-	manager.gateways[key] = gateway
+	if err := manager.add(KindGateway, key, gateway); err != nil {
+		return err
+	}
+
 	manager.logger.Infof("gateway %s added", key)
 
 	return nil
@@ -15,17 +18,17 @@ func (manager *Manager) AddGateway(key string, gateway IGateway) error {
 
 // RemoveGateway ...
 func (manager *Manager) RemoveGateway(key string) (IGateway, error) {
-	gateway := manager.gateways[key]
+	component, err := manager.remove(KindGateway, key)
+	gateway, _ := component.(IGateway)
 
-	delete(manager.configs, key)
 	manager.logger.Infof("gateway %s removed", key)
 
-	return gateway, nil
+	return gateway, err
 }
 
 // GetGateway ...
 func (manager *Manager) GetGateway(key string) IGateway {
-	if gateway, exists := manager.gateways[key]; exists {
+	if gateway, exists := GetComponent[IGateway](manager, KindGateway, key); exists {
 		return gateway
 	}
 	manager.logger.Infof("gateway %s doesn't exist", key)
@@ -0,0 +1,233 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultHealthTimeout is the deadline of the health checks of the handlers
+	defaultHealthTimeout = 5 * time.Second
+	// defaultHealthInterval is the interval of the background health checks that change the state of the components
+	defaultHealthInterval = 10 * time.Second
+)
+
+// IHealthChecker is implemented by the components that can check their dependencies
+type IHealthChecker interface {
+	Healthy(ctx context.Context) error
+}
+
+// HealthStatus ...
+type HealthStatus string
+
+const (
+	HealthStatusUp   HealthStatus = "up"
+	HealthStatusDown HealthStatus = "down"
+)
+
+// ComponentHealth ...
+type ComponentHealth struct {
+	Kind   Kind         `json:"kind"`
+	Key    string       `json:"key"`
+	Status HealthStatus `json:"status"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// HealthReport ...
+type HealthReport struct {
+	Status     HealthStatus       `json:"status"`
+	Components []*ComponentHealth `json:"components"`
+}
+
+// Liveness reports the manager as up when it's started, leaving the checks of the components to the readiness
+func (manager *Manager) Liveness(ctx context.Context) *HealthReport {
+	report := &HealthReport{
+		Status:     HealthStatusUp,
+		Components: []*ComponentHealth{},
+	}
+
+	if !manager.Started() {
+		report.Status = HealthStatusDown
+	}
+
+	return report
+}
+
+// Readiness reports the manager as up when it's started and every component is started and healthy,
+// without changing the state of the components, that is only changed by the background health checks
+func (manager *Manager) Readiness(ctx context.Context) *HealthReport {
+	return manager.health(ctx)
+}
+
+// checkHealth checks the health of the components on every interval until the context is done
+func (manager *Manager) checkHealth(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			manager.updateHealth(ctx)
+		}
+	}
+}
+
+// updateHealth changes the state of the running components that aren't healthy to degraded, and back to running
+func (manager *Manager) updateHealth(ctx context.Context) {
+	ctx, cancel := withTimeout(ctx, manager.healthTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for id, component := range manager.lifecycleComponents() {
+		checker, ok := component.(IHealthChecker)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(id ComponentId, checker IHealthChecker) {
+			defer wg.Done()
+
+			manager.setHealth(id, checker.Healthy(ctx))
+		}(id, checker)
+	}
+	wg.Wait()
+}
+
+func (manager *Manager) health(ctx context.Context) *HealthReport {
+	components := manager.lifecycleComponents()
+	report := &HealthReport{
+		Status:     HealthStatusUp,
+		Components: make([]*ComponentHealth, 0, len(components)),
+	}
+
+	if !manager.Started() {
+		report.Status = HealthStatusDown
+	}
+
+	ids := make([]ComponentId, 0, len(components))
+	for id := range components {
+		ids = append(ids, id)
+	}
+	sortComponentIds(ids)
+
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		health := &ComponentHealth{
+			Kind:   id.Kind,
+			Key:    id.Key,
+			Status: HealthStatusUp,
+		}
+		report.Components = append(report.Components, health)
+
+		wg.Add(1)
+		go func(id ComponentId, health *ComponentHealth, component interface{}) {
+			defer wg.Done()
+
+			var err error
+			if checker, ok := component.(IHealthChecker); ok {
+				err = checker.Healthy(ctx)
+			}
+			if err == nil {
+				if lifecycle, errLifecycle := lifecycleOf(component); errLifecycle != nil {
+					err = errLifecycle
+				} else if !lifecycle.Started() {
+					err = fmt.Errorf("not started")
+				}
+			}
+
+			if err != nil {
+				health.Status = HealthStatusDown
+				health.Error = err.Error()
+			}
+		}(id, health, components[id])
+	}
+	wg.Wait()
+
+	for _, health := range report.Components {
+		if health.Status == HealthStatusDown {
+			report.Status = HealthStatusDown
+		}
+	}
+
+	return report
+}
+
+// LivenessHandler ...
+func (manager *Manager) LivenessHandler() http.Handler {
+	return manager.healthHandler(manager.Liveness)
+}
+
+// ReadinessHandler ...
+func (manager *Manager) ReadinessHandler() http.Handler {
+	return manager.healthHandler(manager.Readiness)
+}
+
+func (manager *Manager) healthHandler(health func(ctx context.Context) *HealthReport) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := withTimeout(r.Context(), manager.healthTimeout)
+		defer cancel()
+
+		report := health(ctx)
+
+		status := http.StatusOK
+		if report.Status != HealthStatusUp {
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			manager.logger.Errorf("error writing health report: %s", err)
+		}
+	})
+}
+
+// AddHealthRoutes mounts the /health/live and /health/ready routes on the web with the given key
+func (manager *Manager) AddHealthRoutes(key string) error {
+	web, err := manager.getHttpHandlerWeb(key)
+	if err != nil {
+		return err
+	}
+
+	if err := web.AddHttpHandler(http.MethodGet, "/health/live", manager.LivenessHandler()); err != nil {
+		return err
+	}
+
+	return web.AddHttpHandler(http.MethodGet, "/health/ready", manager.ReadinessHandler())
+}
+
+func (manager *Manager) getHttpHandlerWeb(key string) (IHttpHandlerWeb, error) {
+	web := manager.GetWeb(key)
+	if web == nil {
+		return nil, fmt.Errorf("web %s doesn't exist", key)
+	}
+
+	handlerWeb, ok := web.(IHttpHandlerWeb)
+	if !ok {
+		return nil, fmt.Errorf("web %s can't serve http handlers", key)
+	}
+
+	return handlerWeb, nil
+}
+
+// runWithContext waits for the function to finish or for the context to be done
+func runWithContext(ctx context.Context, function func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- function()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,209 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testHealthChecker is a component with a health that can be changed by the test
+type testHealthChecker struct {
+	state lifecycleState
+	err   error
+	mux   sync.Mutex
+}
+
+func (component *testHealthChecker) Start(ctx context.Context) error {
+	component.state.setStarted(true)
+	return nil
+}
+
+func (component *testHealthChecker) Stop(ctx context.Context) error {
+	component.state.setStarted(false)
+	return nil
+}
+
+func (component *testHealthChecker) Started() bool {
+	return component.state.isStarted()
+}
+
+func (component *testHealthChecker) Healthy(ctx context.Context) error {
+	component.mux.Lock()
+	defer component.mux.Unlock()
+
+	return component.err
+}
+
+func (component *testHealthChecker) setErr(err error) {
+	component.mux.Lock()
+	defer component.mux.Unlock()
+
+	component.err = err
+}
+
+func serveHealth(t *testing.T, handler http.Handler) (int, *HealthReport) {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	report := &HealthReport{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), report); err != nil {
+		t.Fatal(err)
+	}
+
+	return recorder.Code, report
+}
+
+func TestHealthHandlers(t *testing.T) {
+	manager := NewManager(WithRunInBackground(true), WithHealthInterval(0))
+	component := &testHealthChecker{}
+	if err := manager.Register("checker", "main", component); err != nil {
+		t.Fatal(err)
+	}
+
+	// the manager isn't started yet
+	if status, report := serveHealth(t, manager.LivenessHandler()); status != http.StatusServiceUnavailable || report.Status != HealthStatusDown {
+		t.Fatalf("expected the liveness down before the start, got %d %s", status, report.Status)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Stop()
+
+	var mux sync.Mutex
+	var events []ComponentEvent
+	manager.OnEvent(func(event ComponentEvent) {
+		mux.Lock()
+		defer mux.Unlock()
+
+		events = append(events, event)
+	})
+
+	if status, report := serveHealth(t, manager.ReadinessHandler()); status != http.StatusOK || report.Status != HealthStatusUp {
+		t.Fatalf("expected the readiness up, got %d %s", status, report.Status)
+	}
+
+	component.setErr(fmt.Errorf("unreachable"))
+
+	status, report := serveHealth(t, manager.ReadinessHandler())
+	if status != http.StatusServiceUnavailable || report.Status != HealthStatusDown {
+		t.Fatalf("expected the readiness down, got %d %s", status, report.Status)
+	}
+	if len(report.Components) != 1 || report.Components[0].Key != "main" || report.Components[0].Error != "unreachable" {
+		t.Fatalf("unexpected components %+v", report.Components)
+	}
+
+	// the liveness doesn't check the components
+	if status, report := serveHealth(t, manager.LivenessHandler()); status != http.StatusOK || report.Status != HealthStatusUp || len(report.Components) != 0 {
+		t.Fatalf("expected the liveness up without the components, got %d %+v", status, report)
+	}
+
+	// the readiness only reports the health, without changing the state of the components
+	if state := manager.State("checker", "main"); state != StateRunning {
+		t.Fatalf("expected the state %s, got %s", StateRunning, state)
+	}
+
+	mux.Lock()
+	count := len(events)
+	mux.Unlock()
+	if count != 0 {
+		t.Fatalf("expected no events from the readiness, got %d", count)
+	}
+
+	// a stopped component only takes the readiness down
+	component.setErr(nil)
+	if err := manager.StopComponent("checker", "main"); err != nil {
+		t.Fatal(err)
+	}
+	if status, _ := serveHealth(t, manager.LivenessHandler()); status != http.StatusOK {
+		t.Fatalf("expected the liveness up with a stopped component, got %d", status)
+	}
+	if status, _ := serveHealth(t, manager.ReadinessHandler()); status != http.StatusServiceUnavailable {
+		t.Fatalf("expected the readiness down with a stopped component, got %d", status)
+	}
+}
+
+func TestHealthCheckDegraded(t *testing.T) {
+	manager := NewManager(WithRunInBackground(true), WithHealthInterval(time.Millisecond))
+	component := &testHealthChecker{}
+	if err := manager.Register("checker", "main", component); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Stop()
+
+	waitState := func(expected ComponentState) {
+		deadline := time.Now().Add(time.Second)
+		for manager.State("checker", "main") != expected {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected the state %s, got %s", expected, manager.State("checker", "main"))
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	waitState(StateRunning)
+
+	component.setErr(fmt.Errorf("unreachable"))
+	waitState(StateDegraded)
+
+	component.setErr(nil)
+	waitState(StateRunning)
+}
+
+func TestHealthRoutes(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	host := listener.Addr().String()
+	listener.Close()
+
+	manager := NewManager(WithRunInBackground(true), WithHealthInterval(0))
+	if err := manager.AddWeb("api", manager.NewSimpleWebHttp(host)); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.AddHealthRoutes("missing"); err == nil {
+		t.Fatal("expected an error for the routes on a missing web")
+	}
+	if err := manager.AddHealthRoutes("api"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Stop()
+
+	for _, path := range []string{"/health/live", "/health/ready"} {
+		response, err := http.Get("http://" + host + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		report := &HealthReport{}
+		err = json.NewDecoder(response.Body).Decode(report)
+		response.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if response.StatusCode != http.StatusOK || report.Status != HealthStatusUp {
+			t.Fatalf("expected the manager to be up on %s, got %d %+v", path, response.StatusCode, report)
+		}
+
+		// only the readiness reports the components
+		if expected := path == "/health/ready"; expected != (len(report.Components) == 1 && report.Components[0].Key == "api") {
+			t.Fatalf("unexpected components on %s %+v", path, report.Components)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ILifecycle is the lifecycle of the components started and stopped by the manager
+type ILifecycle interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+	Started() bool
+}
+
+// IWaitGroupLifecycle is the previous lifecycle, that is still supported by wrapping the components with the WaitGroupAdapter
+type IWaitGroupLifecycle interface {
+	Start(waitGroup ...*sync.WaitGroup) error
+	Stop(waitGroup ...*sync.WaitGroup) error
+	Started() bool
+}
+
+// IDrainable is implemented by the components that can stop taking new work and finish the work in flight,
+// before being stopped
+type IDrainable interface {
+	Drain(ctx context.Context) error
+}
+
+// IPausable is implemented by the components that can stop and resume taking new work while started, as the consumers
+type IPausable interface {
+	Pause() error
+	Resume() error
+}
+
+// Timeouts are the deadlines of the lifecycle of a component, where zero means without deadline
+type Timeouts struct {
+	Start time.Duration `json:"start"`
+	Drain time.Duration `json:"drain"`
+	Stop  time.Duration `json:"stop"`
+}
+
+// NewTimeouts ...
+func NewTimeouts(start, drain, stop time.Duration) *Timeouts {
+	return &Timeouts{
+		Start: start,
+		Drain: drain,
+		Stop:  stop,
+	}
+}
+
+// WaitGroupAdapter ...
+type WaitGroupAdapter struct {
+	component IWaitGroupLifecycle
+}
+
+// NewWaitGroupAdapter ...
+func NewWaitGroupAdapter(component IWaitGroupLifecycle) *WaitGroupAdapter {
+	return &WaitGroupAdapter{
+		component: component,
+	}
+}
+
+// Start ...
+func (adapter *WaitGroupAdapter) Start(ctx context.Context) error {
+	return adapter.execute(ctx, adapter.component.Start)
+}
+
+// Stop ...
+func (adapter *WaitGroupAdapter) Stop(ctx context.Context) error {
+	return adapter.execute(ctx, adapter.component.Stop)
+}
+
+// Started ...
+func (adapter *WaitGroupAdapter) Started() bool {
+	return adapter.component.Started()
+}
+
+// execute waits for the action to finish or for the context to be done,
+// leaving the action running in the background on the latter
+func (adapter *WaitGroupAdapter) execute(ctx context.Context, action func(waitGroup ...*sync.WaitGroup) error) error {
+	done := make(chan error, 1)
+
+	go func() {
+		wg := &sync.WaitGroup{}
+		wg.Add(1)
+		done <- action(wg)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// lifecycleOf returns the lifecycle of the component, where the components with the previous lifecycle
+// should be wrapped with the WaitGroupAdapter
+func lifecycleOf(component interface{}) (ILifecycle, error) {
+	switch c := component.(type) {
+	case ILifecycle:
+		return c, nil
+	case IWaitGroupLifecycle:
+		return nil, fmt.Errorf("the component %T has the previous lifecycle, it should be wrapped with NewWaitGroupAdapter", component)
+	default:
+		return nil, fmt.Errorf("the component %T doesn't implement a lifecycle", component)
+	}
+}
+
+// SetTimeouts sets the deadlines of the lifecycle of the component with the given kind and key,
+// overriding the default ones of the manager
+func (manager *Manager) SetTimeouts(kind Kind, key string, timeouts *Timeouts) error {
+	id := NewComponentId(kind, key)
+	if err := validateKind(id); err != nil {
+		return err
+	}
+
+	manager.mux.Lock()
+	manager.timeouts[id] = timeouts
+	manager.mux.Unlock()
+
+	manager.logger.Infof("timeouts of %s set", id)
+
+	return nil
+}
+
+// GetTimeouts returns the deadlines of the lifecycle of the component with the given kind and key
+func (manager *Manager) GetTimeouts(kind Kind, key string) *Timeouts {
+	manager.mux.RLock()
+	defer manager.mux.RUnlock()
+
+	timeouts := *manager.defaultTimeouts
+
+	if custom, exists := manager.timeouts[NewComponentId(kind, key)]; exists {
+		if custom.Start > 0 {
+			timeouts.Start = custom.Start
+		}
+		if custom.Drain > 0 {
+			timeouts.Drain = custom.Drain
+		}
+		if custom.Stop > 0 {
+			timeouts.Stop = custom.Stop
+		}
+	}
+
+	return &timeouts
+}
+
+// Context is canceled when the manager stops, after the components are drained
+func (manager *Manager) Context() context.Context {
+	manager.mux.RLock()
+	defer manager.mux.RUnlock()
+
+	return manager.ctx
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// lifecycleState is the started state of a component, safe for concurrent use,
+// where lock serializes the lifecycle calls without blocking the ones to isStarted
+type lifecycleState struct {
+	lifecycle sync.Mutex
+	mux       sync.RWMutex
+	started   bool
+}
+
+func (state *lifecycleState) lock() {
+	state.lifecycle.Lock()
+}
+
+func (state *lifecycleState) unlock() {
+	state.lifecycle.Unlock()
+}
+
+func (state *lifecycleState) isStarted() bool {
+	state.mux.RLock()
+	defer state.mux.RUnlock()
+
+	return state.started
+}
+
+func (state *lifecycleState) setStarted(started bool) {
+	state.mux.Lock()
+	defer state.mux.Unlock()
+
+	state.started = started
+}
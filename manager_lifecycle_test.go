@@ -0,0 +1,116 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTimeouts(t *testing.T) {
+	manager := NewManager(WithRunInBackground(true), WithTimeouts(NewTimeouts(time.Hour, time.Hour, time.Hour)))
+	calls := &testCalls{}
+
+	if err := manager.Register(KindProcess, "slow", &testComponent{name: "slow", calls: calls, blockStart: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetTimeouts(KindProcess, "slow", NewTimeouts(10*time.Millisecond, 0, 10*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetTimeouts(KindConfig, "app", NewTimeouts(time.Second, 0, 0)); err == nil {
+		t.Fatal("expected an error for the timeouts of a component without a lifecycle")
+	}
+
+	// the zero timeouts keep the default ones of the manager
+	if timeouts := manager.GetTimeouts(KindProcess, "slow"); *timeouts != *NewTimeouts(10*time.Millisecond, time.Hour, 10*time.Millisecond) {
+		t.Fatalf("unexpected timeouts %+v", timeouts)
+	}
+
+	start := time.Now()
+	if err := manager.Start(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the start deadline to be exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the start to fail on the timeout of the component, took %s", elapsed)
+	}
+	if err := manager.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	manager = NewManager(WithRunInBackground(true), WithTimeouts(NewTimeouts(0, 0, 10*time.Millisecond)))
+	if err := manager.Register(KindProcess, "slow", &testComponent{name: "slow", calls: calls, blockStop: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Stop(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the stop deadline to be exceeded, got %v", err)
+	}
+}
+
+// testWaitGroupComponent is a component with the previous lifecycle, that is done with the wait group
+type testWaitGroupComponent struct {
+	started bool
+	mux     sync.Mutex
+}
+
+func (component *testWaitGroupComponent) Start(waitGroup ...*sync.WaitGroup) error {
+	component.mux.Lock()
+	defer component.mux.Unlock()
+
+	component.started = true
+	if len(waitGroup) > 0 {
+		waitGroup[0].Done()
+	}
+
+	return nil
+}
+
+func (component *testWaitGroupComponent) Stop(waitGroup ...*sync.WaitGroup) error {
+	component.mux.Lock()
+	defer component.mux.Unlock()
+
+	component.started = false
+	if len(waitGroup) > 0 {
+		waitGroup[0].Done()
+	}
+
+	return nil
+}
+
+func (component *testWaitGroupComponent) Started() bool {
+	component.mux.Lock()
+	defer component.mux.Unlock()
+
+	return component.started
+}
+
+func TestWaitGroupAdapter(t *testing.T) {
+	manager := newTestManager()
+
+	// the components with the previous lifecycle are only added through the adapter
+	component := &testWaitGroupComponent{}
+	if err := manager.Register(KindProcess, "legacy", component); err == nil {
+		t.Fatal("expected an error adding a component with the previous lifecycle without the adapter")
+	}
+	if err := manager.Register(KindProcess, "legacy", NewWaitGroupAdapter(component)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if !component.Started() || manager.State(KindProcess, "legacy") != StateRunning {
+		t.Fatal("expected the component with the previous lifecycle to be started")
+	}
+
+	if err := manager.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if component.Started() {
+		t.Fatal("expected the component with the previous lifecycle to be stopped")
+	}
+}
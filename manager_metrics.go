@@ -0,0 +1,475 @@
+package manager
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricType ...
+type MetricType string
+
+const (
+	MetricTypeCounter   MetricType = "counter"
+	MetricTypeGauge     MetricType = "gauge"
+	MetricTypeHistogram MetricType = "histogram"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency histograms
+var DefaultLatencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Labels ...
+type Labels map[string]string
+
+// Metrics is a registry of counters, gauges and histograms, written in the prometheus text format,
+// where the registry is locked to add series and each series is locked on its own to be updated
+type Metrics struct {
+	families   map[string]*metricFamily
+	collectors []func(metrics *Metrics)
+	mux        *sync.Mutex
+}
+
+type metricFamily struct {
+	name       string
+	help       string
+	metricType MetricType
+	buckets    []float64
+	series     map[string]*metricSeries
+}
+
+type metricSeries struct {
+	labels  string
+	value   float64
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+	mux     sync.Mutex
+}
+
+// NewMetrics ...
+func NewMetrics() *Metrics {
+	return &Metrics{
+		families: make(map[string]*metricFamily),
+		mux:      &sync.Mutex{},
+	}
+}
+
+// Counter returns the counter with the given name and labels, registering it on the first call
+func (metrics *Metrics) Counter(name, help string, labels Labels) *Counter {
+	if metrics == nil {
+		return nil
+	}
+	return &Counter{series: metrics.series(name, help, MetricTypeCounter, nil, labels)}
+}
+
+// Gauge returns the gauge with the given name and labels, registering it on the first call
+func (metrics *Metrics) Gauge(name, help string, labels Labels) *Gauge {
+	if metrics == nil {
+		return nil
+	}
+	return &Gauge{series: metrics.series(name, help, MetricTypeGauge, nil, labels)}
+}
+
+// Histogram returns the histogram with the given name and labels, registering it on the first call,
+// with the default latency buckets when none are given
+func (metrics *Metrics) Histogram(name, help string, buckets []float64, labels Labels) *Histogram {
+	if metrics == nil {
+		return nil
+	}
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	return &Histogram{series: metrics.series(name, help, MetricTypeHistogram, buckets, labels)}
+}
+
+// AddCollector adds a function that updates metrics before they are written, for values read on demand
+func (metrics *Metrics) AddCollector(collector func(metrics *Metrics)) {
+	metrics.mux.Lock()
+	defer metrics.mux.Unlock()
+
+	metrics.collectors = append(metrics.collectors, collector)
+}
+
+func (metrics *Metrics) series(name, help string, metricType MetricType, buckets []float64, labels Labels) *metricSeries {
+	metrics.mux.Lock()
+	defer metrics.mux.Unlock()
+
+	family, exists := metrics.families[name]
+	if !exists {
+		family = &metricFamily{
+			name:       name,
+			help:       help,
+			metricType: metricType,
+			buckets:    buckets,
+			series:     make(map[string]*metricSeries),
+		}
+		metrics.families[name] = family
+	}
+
+	key := formatLabels(labels)
+	if family.metricType != metricType {
+		// a metric with the same name and another type isn't registered
+		return newMetricSeries(key, buckets)
+	}
+
+	series, exists := family.series[key]
+	if !exists {
+		series = newMetricSeries(key, family.buckets)
+		family.series[key] = series
+	}
+
+	return series
+}
+
+func newMetricSeries(labels string, buckets []float64) *metricSeries {
+	return &metricSeries{
+		labels:  labels,
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+// Write runs the collectors and writes the metrics in the prometheus text format
+func (metrics *Metrics) Write(writer io.Writer) error {
+	metrics.mux.Lock()
+	collectors := make([]func(metrics *Metrics), len(metrics.collectors))
+	copy(collectors, metrics.collectors)
+	metrics.mux.Unlock()
+
+	for _, collector := range collectors {
+		collector(metrics)
+	}
+
+	metrics.mux.Lock()
+	defer metrics.mux.Unlock()
+
+	names := make([]string, 0, len(metrics.families))
+	for name := range metrics.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buffer := bufio.NewWriter(writer)
+	for _, name := range names {
+		metrics.families[name].write(buffer)
+	}
+
+	return buffer.Flush()
+}
+
+// Handler serves the metrics in the prometheus text format
+func (metrics *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := metrics.Write(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+func (family *metricFamily) write(writer *bufio.Writer) {
+	keys := make([]string, 0, len(family.series))
+	for key := range family.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(writer, "# HELP %s %s\n", family.name, escapeHelp(family.help))
+	fmt.Fprintf(writer, "# TYPE %s %s\n", family.name, family.metricType)
+
+	for _, key := range keys {
+		family.series[key].write(writer, family)
+	}
+}
+
+func (series *metricSeries) write(writer *bufio.Writer, family *metricFamily) {
+	series.mux.Lock()
+	defer series.mux.Unlock()
+
+	if family.metricType != MetricTypeHistogram {
+		fmt.Fprintf(writer, "%s%s %s\n", family.name, wrapLabels(series.labels), formatFloat(series.value))
+		return
+	}
+
+	var cumulative uint64
+	for i, bound := range series.buckets {
+		cumulative += series.counts[i]
+		fmt.Fprintf(writer, "%s_bucket%s %d\n", family.name, wrapLabels(joinLabels(series.labels, fmt.Sprintf("le=%q", formatFloat(bound)))), cumulative)
+	}
+	fmt.Fprintf(writer, "%s_bucket%s %d\n", family.name, wrapLabels(joinLabels(series.labels, `le="+Inf"`)), series.count)
+	fmt.Fprintf(writer, "%s_sum%s %s\n", family.name, wrapLabels(series.labels), formatFloat(series.sum))
+	fmt.Fprintf(writer, "%s_count%s %d\n", family.name, wrapLabels(series.labels), series.count)
+}
+
+// Counter ...
+type Counter struct {
+	series *metricSeries
+}
+
+// Inc ...
+func (counter *Counter) Inc() {
+	counter.Add(1)
+}
+
+// Add adds the value, ignoring negative values as a counter only goes up
+func (counter *Counter) Add(value float64) {
+	if counter == nil || value < 0 {
+		return
+	}
+
+	counter.series.mux.Lock()
+	defer counter.series.mux.Unlock()
+
+	counter.series.value += value
+}
+
+// Set sets the total of a counter collected from a source that counts it on its own, as the stats of a database,
+// where a lower total is a reset of the source, as when the database is replaced
+func (counter *Counter) Set(value float64) {
+	if counter == nil || value < 0 {
+		return
+	}
+
+	counter.series.mux.Lock()
+	defer counter.series.mux.Unlock()
+
+	counter.series.value = value
+}
+
+// Gauge ...
+type Gauge struct {
+	series *metricSeries
+}
+
+// Set ...
+func (gauge *Gauge) Set(value float64) {
+	if gauge == nil {
+		return
+	}
+
+	gauge.series.mux.Lock()
+	defer gauge.series.mux.Unlock()
+
+	gauge.series.value = value
+}
+
+// Add ...
+func (gauge *Gauge) Add(value float64) {
+	if gauge == nil {
+		return
+	}
+
+	gauge.series.mux.Lock()
+	defer gauge.series.mux.Unlock()
+
+	gauge.series.value += value
+}
+
+// Histogram ...
+type Histogram struct {
+	series *metricSeries
+}
+
+// Observe ...
+func (histogram *Histogram) Observe(value float64) {
+	if histogram == nil {
+		return
+	}
+
+	histogram.series.mux.Lock()
+	defer histogram.series.mux.Unlock()
+
+	series := histogram.series
+	for i, bound := range series.buckets {
+		if value <= bound {
+			series.counts[i]++
+			break
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+// ObserveDuration observes the seconds since the given time
+func (histogram *Histogram) ObserveDuration(start time.Time) {
+	histogram.Observe(time.Since(start).Seconds())
+}
+
+// formatLabels formats the labels sorted by name, as the key of the series
+func formatLabels(labels Labels) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escapeLabel(labels[name])))
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func joinLabels(labels, label string) string {
+	if labels == "" {
+		return label
+	}
+	return labels + "," + label
+}
+
+func wrapLabels(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func escapeHelp(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(value)
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+// Metrics returns the metrics registry of the manager
+func (manager *Manager) Metrics() *Metrics {
+	return manager.metrics
+}
+
+// MetricsHandler serves the metrics of the manager in the prometheus text format
+func (manager *Manager) MetricsHandler() http.Handler {
+	return manager.metrics.Handler()
+}
+
+// AddMetricsRoute mounts the /metrics route on the web with the given key
+func (manager *Manager) AddMetricsRoute(key string) error {
+	web, err := manager.getHttpHandlerWeb(key)
+	if err != nil {
+		return err
+	}
+
+	return web.AddHttpHandler(http.MethodGet, "/metrics", manager.MetricsHandler())
+}
+
+// collectDBStats updates the pool stats of the databases
+func (manager *Manager) collectDBStats(metrics *Metrics) {
+	for _, id := range manager.registry.Ids(KindDB) {
+		db, ok := GetComponent[IDB](manager, KindDB, id.Key)
+		if !ok {
+			continue
+		}
+
+		if !db.Started() {
+			continue
+		}
+
+		stats := db.Stats()
+		labels := Labels{"db": id.Key}
+
+		metrics.Gauge("manager_db_max_open_connections", "Maximum number of open connections to the database.", labels).Set(float64(stats.MaxOpenConnections))
+		metrics.Gauge("manager_db_open_connections", "Number of established connections to the database.", labels).Set(float64(stats.OpenConnections))
+		metrics.Gauge("manager_db_in_use_connections", "Number of connections in use.", labels).Set(float64(stats.InUse))
+		metrics.Gauge("manager_db_idle_connections", "Number of idle connections.", labels).Set(float64(stats.Idle))
+		metrics.Counter("manager_db_wait_count_total", "Total number of connections waited for.", labels).Set(float64(stats.WaitCount))
+		metrics.Counter("manager_db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.", labels).Set(stats.WaitDuration.Seconds())
+	}
+}
+
+// workMetrics instruments the workers of a work list
+type workMetrics struct {
+	processed *Counter
+	failed    *Counter
+	requeued  *Counter
+	discarded *Counter
+	latency   *Histogram
+}
+
+func newWorkMetrics(metrics *Metrics, name string) *workMetrics {
+	labels := Labels{"worklist": name}
+
+	return &workMetrics{
+		processed: metrics.Counter("manager_worklist_works_processed_total", "Number of works handled with success.", labels),
+		failed:    metrics.Counter("manager_worklist_works_failed_total", "Number of works handled with error.", labels),
+		requeued:  metrics.Counter("manager_worklist_works_requeued_total", "Number of failed works added again to the list.", labels),
+		discarded: metrics.Counter("manager_worklist_works_discarded_total", "Number of failed works discarded after the max retries.", labels),
+		latency:   metrics.Histogram("manager_worklist_handler_duration_seconds", "Latency of the work handler.", nil, labels),
+	}
+}
+
+// observe counts the works handled and observes the latency of the handler
+func (metrics *workMetrics) observe(start time.Time, works int, err error) {
+	if metrics == nil {
+		return
+	}
+
+	metrics.latency.ObserveDuration(start)
+	if err != nil {
+		metrics.failed.Add(float64(works))
+	} else {
+		metrics.processed.Add(float64(works))
+	}
+}
+
+func (metrics *workMetrics) requeue(works int) {
+	if metrics != nil {
+		metrics.requeued.Add(float64(works))
+	}
+}
+
+func (metrics *workMetrics) discard(works int) {
+	if metrics != nil {
+		metrics.discarded.Add(float64(works))
+	}
+}
+
+// messageMetrics instruments the producers and consumers of messages
+type messageMetrics struct {
+	messages *Counter
+	errors   *Counter
+	acks     *Counter
+	nacks    *Counter
+}
+
+// newMessageMetrics counts the messages and the errors, and the acks and the nacks of the ones that have them
+func newMessageMetrics(metrics *Metrics, prefix string, labels Labels, acks, nacks bool) *messageMetrics {
+	messageMetrics := &messageMetrics{
+		messages: metrics.Counter(prefix+"_messages_total", "Number of messages.", labels),
+		errors:   metrics.Counter(prefix+"_errors_total", "Number of messages with error.", labels),
+	}
+
+	if acks {
+		messageMetrics.acks = metrics.Counter(prefix+"_acks_total", "Number of messages acknowledged.", labels)
+	}
+	if nacks {
+		messageMetrics.nacks = metrics.Counter(prefix+"_nacks_total", "Number of messages not acknowledged.", labels)
+	}
+
+	return messageMetrics
+}
+
+// routeLatency returns the latency histogram of a web route
+func routeLatency(metrics *Metrics, host, method, path string) *Histogram {
+	return metrics.Histogram("manager_web_request_duration_seconds", "Latency of the web routes.", nil,
+		Labels{"host": host, "method": method, "path": path})
+}
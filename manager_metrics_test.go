@@ -0,0 +1,85 @@
+package manager
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMetricsWrite(t *testing.T) {
+	metrics := NewMetrics()
+
+	requests := metrics.Counter("requests_total", "Number of requests.", Labels{"path": "/orders", "method": "GET"})
+	latency := metrics.Histogram("latency_seconds", "Latency.", []float64{.1, 1}, nil)
+
+	// the series are updated concurrently, each on its own lock
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			requests.Inc()
+		}()
+		go func() {
+			defer wg.Done()
+			latency.Observe(.5)
+		}()
+	}
+	wg.Wait()
+
+	metrics.Gauge("workers", "Number of\nworkers.", nil).Set(3)
+	metrics.Counter("requests_total", "Number of requests.", Labels{"path": "/orders", "method": "GET"}).Add(-1)
+	metrics.AddCollector(func(metrics *Metrics) {
+		metrics.Gauge("queue_size", "Size of the queue.", Labels{"queue": `a"b`}).Set(1.5)
+		// the totals counted by the source are set on the counters
+		metrics.Counter("waits_total", "Number of waits.", nil).Set(7)
+	})
+
+	var buffer bytes.Buffer
+	if err := metrics.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		`# HELP latency_seconds Latency.`,
+		`# TYPE latency_seconds histogram`,
+		`latency_seconds_bucket{le="0.1"} 0`,
+		`latency_seconds_bucket{le="1"} 50`,
+		`latency_seconds_bucket{le="+Inf"} 50`,
+		`latency_seconds_sum 25`,
+		`latency_seconds_count 50`,
+		`# HELP queue_size Size of the queue.`,
+		`# TYPE queue_size gauge`,
+		`queue_size{queue="a\"b"} 1.5`,
+		`# HELP requests_total Number of requests.`,
+		`# TYPE requests_total counter`,
+		`requests_total{method="GET",path="/orders"} 50`,
+		`# HELP waits_total Number of waits.`,
+		`# TYPE waits_total counter`,
+		`waits_total 7`,
+		`# HELP workers Number of\nworkers.`,
+		`# TYPE workers gauge`,
+		`workers 3`,
+	}, "\n") + "\n"
+
+	if buffer.String() != expected {
+		t.Fatalf("unexpected exposition\n%s\nexpected\n%s", buffer.String(), expected)
+	}
+}
+
+func TestRabbitmqConsumerMetrics(t *testing.T) {
+	manager := newTestManager()
+	if _, err := manager.NewSimpleRabbitmqConsumer(&RabbitmqConfig{Exchange: "orders"}, "queue", "key", "tag", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	if err := manager.Metrics().Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buffer.String(), "manager_rabbitmq_consumer_acks_total") || strings.Contains(buffer.String(), "nacks") {
+		t.Fatalf("expected the acks without the nacks of the rabbitmq consumer\n%s", buffer.String())
+	}
+}
@@ -2,12 +2,12 @@ package manager
 
 // NSQConfig ...
 type NSQConfig struct {
-	Lookupd      []string `json:"lookupd"`
+	Lookupd      []string `json:"lookupd" validate:"required"`
 	Nsqd         []string `json:"nsqd"`
-	Topic        string   `json:"topic"`
+	Topic        string   `json:"topic" validate:"required"`
 	Channel      string   `json:"channel"`
 	RequeueDelay int64    `json:"requeue_delay"`
-	MaxInFlight  int      `json:"max_in_flight"`
+	MaxInFlight  int      `json:"max_in_flight" validate:"min=0"`
 	MaxAttempts  uint16   `json:"max_attempts"`
 	AutoRespond  bool     `json:"auto_respond"`
 }
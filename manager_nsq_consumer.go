@@ -1,8 +1,9 @@
 package manager
 
 import (
+	"context"
+
 	"github.com/nsqio/go-nsq"
-	"sync"
 )
 
 type INSQHandler interface {
@@ -11,15 +12,18 @@ type INSQHandler interface {
 
 // INSQConsumer ...
 type INSQConsumer interface {
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
 	HandleMessage(message *nsq.Message) error
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
 	Started() bool
 }
 
 // AddNSQConsumer ...
 func (manager *Manager) AddNSQConsumer(key string, nsqConsumer INSQConsumer) error {
-	manager.nsqConsumers[key] = nsqConsumer
+	if err := manager.add(KindNSQConsumer, key, nsqConsumer); err != nil {
+		return err
+	}
+
 	manager.logger.Infof("consumer %s added", key)
 
 	return nil
@@ -27,17 +31,17 @@ func (manager *Manager) AddNSQConsumer(key string, nsqConsumer INSQConsumer) err
 
 // RemoveNSQConsumer ...
 func (manager *Manager) RemoveNSQConsumer(key string) (INSQConsumer, error) {
-	nsqConsumer := manager.nsqConsumers[key]
+	component, err := manager.remove(KindNSQConsumer, key)
+	nsqConsumer, _ := component.(INSQConsumer)
 
-	delete(manager.processes, key)
 	manager.logger.Infof("consumer %s removed", key)
 
-	return nsqConsumer, nil
+	return nsqConsumer, err
 }
 
 // GetNSQConsumer ...
 func (manager *Manager) GetNSQConsumer(key string) INSQConsumer {
-	if nsqConsumer, exists := manager.nsqConsumers[key]; exists {
+	if nsqConsumer, exists := GetComponent[INSQConsumer](manager, KindNSQConsumer, key); exists {
 		return nsqConsumer
 	}
 	manager.logger.Infof("consumer %s doesn't exist", key)
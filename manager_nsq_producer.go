@@ -1,19 +1,22 @@
 package manager
 
-import "sync"
+import "context"
 
 // INSQProducer ...
 type INSQProducer interface {
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
 	Publish(topic string, body []byte, maxRetries int) error
 	Ping() error
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
 	Started() bool
 }
 
 // AddNSQProducer ...
 func (manager *Manager) AddNSQProducer(key string, nsqProducer INSQProducer) error {
-	manager.nsqProducers[key] = nsqProducer
+	if err := manager.add(KindNSQProducer, key, nsqProducer); err != nil {
+		return err
+	}
+
 	manager.logger.Infof("nsq producer %s added", key)
 
 	return nil
@@ -21,17 +24,17 @@ func (manager *Manager) AddNSQProducer(key string, nsqProducer INSQProducer) err
 
 // RemoveNSQProducer ...
 func (manager *Manager) RemoveNSQProducer(key string) (INSQProducer, error) {
-	process := manager.nsqProducers[key]
+	component, err := manager.remove(KindNSQProducer, key)
+	process, _ := component.(INSQProducer)
 
-	delete(manager.processes, key)
 	manager.logger.Infof("nsq producer %s removed", key)
 
-	return process, nil
+	return process, err
 }
 
 // GetNSQProducer ...
 func (manager *Manager) GetNSQProducer(key string) INSQProducer {
-	if process, exists := manager.nsqProducers[key]; exists {
+	if process, exists := GetComponent[INSQProducer](manager, KindNSQProducer, key); exists {
 		return process
 	}
 	manager.logger.Infof("nsq producer %s doesn't exist", key)
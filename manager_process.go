@@ -1,17 +1,20 @@
 package manager
 
-import "sync"
+import "context"
 
-// IProcess ...
+// IProcess is started and stopped by the manager, where the processes with the previous lifecycle are added with the WaitGroupAdapter
 type IProcess interface {
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
 	Started() bool
 }
 
 // AddProcess ...
 func (manager *Manager) AddProcess(key string, process IProcess) error {
-	manager.processes[key] = process
+	if err := manager.add(KindProcess, key, process); err != nil {
+		return err
+	}
+
 	manager.logger.Infof("process %s added", key)
 
 	return nil
@@ -19,17 +22,17 @@ func (manager *Manager) AddProcess(key string, process IProcess) error {
 
 // RemoveProcess ...
 func (manager *Manager) RemoveProcess(key string) (IProcess, error) {
-	process := manager.processes[key]
+	component, err := manager.remove(KindProcess, key)
+	process, _ := component.(IProcess)
 
-	delete(manager.processes, key)
 	manager.logger.Infof("process %s removed", key)
 
-	return process, nil
+	return process, err
 }
 
 // GetProcess ...
 func (manager *Manager) GetProcess(key string) IProcess {
-	if process, exists := manager.processes[key]; exists {
+	if process, exists := GetComponent[IProcess](manager, KindProcess, key); exists {
 		return process
 	}
 	manager.logger.Infof("process %s doesn't exist", key)
@@ -2,9 +2,9 @@ package manager
 
 // RabbitmqConfig ...
 type RabbitmqConfig struct {
-	Uri          string `json:"uri"`
-	Exchange     string `json:"exchange"`
-	ExchangeType string `json:"exchange_type"`
+	Uri          string `json:"uri" validate:"required,url"`
+	Exchange     string `json:"exchange" validate:"required"`
+	ExchangeType string `json:"exchange_type" validate:"required,oneof=direct fanout topic headers"`
 }
 
 // NewRabbitmqConfig...
@@ -1,22 +1,26 @@
 package manager
 
 import (
+	"context"
+
 	"github.com/streadway/amqp"
-	"sync"
 )
 
 type RabbitmqHandler func(message amqp.Delivery) error
 
 // IRabbitmqConsumer ...
 type IRabbitmqConsumer interface {
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
 	Started() bool
 }
 
 // AddRabbitmqConsumer ...
 func (manager *Manager) AddRabbitmqConsumer(key string, rabbitmqConsumer IRabbitmqConsumer) error {
-	manager.rabbitmqConsumers[key] = rabbitmqConsumer
+	if err := manager.add(KindRabbitmqConsumer, key, rabbitmqConsumer); err != nil {
+		return err
+	}
+
 	manager.logger.Infof("consumer %s added", key)
 
 	return nil
@@ -24,17 +28,17 @@ func (manager *Manager) AddRabbitmqConsumer(key string, rabbitmqConsumer IRabbit
 
 // RemoveRabbitmqConsumer ...
 func (manager *Manager) RemoveRabbitmqConsumer(key string) (IRabbitmqConsumer, error) {
-	rabbitmqConsumer := manager.rabbitmqConsumers[key]
+	component, err := manager.remove(KindRabbitmqConsumer, key)
+	rabbitmqConsumer, _ := component.(IRabbitmqConsumer)
 
-	delete(manager.processes, key)
 	manager.logger.Infof("consumer %s removed", key)
 
-	return rabbitmqConsumer, nil
+	return rabbitmqConsumer, err
 }
 
 // GetRabbitmqConsumer ...
 func (manager *Manager) GetRabbitmqConsumer(key string) IRabbitmqConsumer {
-	if rabbitmqConsumer, exists := manager.rabbitmqConsumers[key]; exists {
+	if rabbitmqConsumer, exists := GetComponent[IRabbitmqConsumer](manager, KindRabbitmqConsumer, key); exists {
 		return rabbitmqConsumer
 	}
 	manager.logger.Infof("consumer %s doesn't exist", key)
@@ -1,18 +1,21 @@
 package manager
 
-import "sync"
+import "context"
 
 // IRabbitmqProducer ...
 type IRabbitmqProducer interface {
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
 	Publish(routingKey string, body []byte, reliable bool) error
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
 	Started() bool
 }
 
 // AddRabbitmqProducer ...
 func (manager *Manager) AddRabbitmqProducer(key string, nsqProducer IRabbitmqProducer) error {
-	manager.rabbitmqProducers[key] = nsqProducer
+	if err := manager.add(KindRabbitmqProducer, key, nsqProducer); err != nil {
+		return err
+	}
+
 	manager.logger.Infof("nsq producer %s added", key)
 
 	return nil
@@ -20,17 +23,17 @@ func (manager *Manager) AddRabbitmqProducer(key string, nsqProducer IRabbitmqPro
 
 // RemoveRabbitmqProducer ...
 func (manager *Manager) RemoveRabbitmqProducer(key string) (IRabbitmqProducer, error) {
-	process := manager.rabbitmqProducers[key]
+	component, err := manager.remove(KindRabbitmqProducer, key)
+	process, _ := component.(IRabbitmqProducer)
 
-	delete(manager.processes, key)
 	manager.logger.Infof("nsq producer %s removed", key)
 
-	return process, nil
+	return process, err
 }
 
 // GetRabbitmqProducer ...
 func (manager *Manager) GetRabbitmqProducer(key string) IRabbitmqProducer {
-	if process, exists := manager.rabbitmqProducers[key]; exists {
+	if process, exists := GetComponent[IRabbitmqProducer](manager, KindRabbitmqProducer, key); exists {
 		return process
 	}
 	manager.logger.Infof("nsq producer %s doesn't exist", key)
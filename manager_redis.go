@@ -1,10 +1,13 @@
 package manager
 
-import "sync"
+import (
+	"context"
+	"time"
+)
 
 type IRedis interface {
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
 	Started() bool
 
 	Action(command string, arguments ...string) error
@@ -78,10 +81,30 @@ type IRedis interface {
 	Publish(channel string, message []byte) (recieverCout int64, err error)
 }
 
+// RedisMessageHandler ...
+type RedisMessageHandler func(message []byte)
+
+// IRedisSubscriber is implemented by the redis that can subscribe to the channels of Publish,
+// calling the handler with each message until the context is done
+type IRedisSubscriber interface {
+	Subscribe(ctx context.Context, channel string, handler RedisMessageHandler) error
+}
+
+// IRedisLease is implemented by the redis that can take, renew and release a lease on a key atomically,
+// so that a replica never extends or deletes a lease taken over by another one
+type IRedisLease interface {
+	// AcquireLease sets the key to the value with the time to live when the key doesn't exist, as SET key value NX PX ttl
+	AcquireLease(key string, value []byte, ttl time.Duration) (bool, error)
+	// RenewLease sets the time to live of the key when it still has the value
+	RenewLease(key string, value []byte, ttl time.Duration) (bool, error)
+	// ReleaseLease deletes the key when it still has the value
+	ReleaseLease(key string, value []byte) (bool, error)
+}
+
 // RedisConfig ...
 type RedisConfig struct {
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
+	Host     string `json:"host" validate:"required"`
+	Port     int    `json:"port" validate:"min=0,max=65535"`
 	Database int    `json:"database"`
 	Password string `json:"password"`
 }
@@ -98,7 +121,10 @@ func NewRedisConfig(host string, port int, database int, password string) *Redis
 
 // AddRedis ...
 func (manager *Manager) AddRedis(key string, redis IRedis) error {
-	manager.redis[key] = redis
+	if err := manager.add(KindRedis, key, redis); err != nil {
+		return err
+	}
+
 	manager.logger.Infof("redis %s added", key)
 
 	return nil
@@ -106,17 +132,17 @@ func (manager *Manager) AddRedis(key string, redis IRedis) error {
 
 // RemoveRedis ...
 func (manager *Manager) RemoveRedis(key string) (IRedis, error) {
-	redis := manager.redis[key]
+	component, err := manager.remove(KindRedis, key)
+	redis, _ := component.(IRedis)
 
-	delete(manager.redis, key)
 	manager.logger.Infof("redis %s removed", key)
 
-	return redis, nil
+	return redis, err
 }
 
 // GetRedis ...
 func (manager *Manager) GetRedis(key string) interface{} {
-	if redis, exists := manager.redis[key]; exists {
+	if redis, exists := GetComponent[IRedis](manager, KindRedis, key); exists {
 		return redis
 	}
 	manager.logger.Infof("redis %s doesn't exist", key)
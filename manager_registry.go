@@ -0,0 +1,210 @@
+package manager
+
+import (
+	"reflect"
+	"sync"
+)
+
+// kindTypes are the interfaces of the components of the known kinds
+var kindTypes = map[Kind]reflect.Type{
+	KindDB:               reflect.TypeOf((*IDB)(nil)).Elem(),
+	KindNSQProducer:      reflect.TypeOf((*INSQProducer)(nil)).Elem(),
+	KindNSQConsumer:      reflect.TypeOf((*INSQConsumer)(nil)).Elem(),
+	KindRabbitmqProducer: reflect.TypeOf((*IRabbitmqProducer)(nil)).Elem(),
+	KindRabbitmqConsumer: reflect.TypeOf((*IRabbitmqConsumer)(nil)).Elem(),
+	KindRedis:            reflect.TypeOf((*IRedis)(nil)).Elem(),
+	KindWorkList:         reflect.TypeOf((*IWorkList)(nil)).Elem(),
+	KindProcess:          reflect.TypeOf((*IProcess)(nil)).Elem(),
+	KindWeb:              reflect.TypeOf((*IWeb)(nil)).Elem(),
+	KindConfig:           reflect.TypeOf((*IConfig)(nil)).Elem(),
+	KindGateway:          reflect.TypeOf((*IGateway)(nil)).Elem(),
+}
+
+// Registry keeps items by kind and key, safe for concurrent use
+type Registry[T any] struct {
+	items map[ComponentId]T
+	mux   *sync.RWMutex
+}
+
+// NewRegistry ...
+func NewRegistry[T any]() *Registry[T] {
+	return &Registry[T]{
+		items: make(map[ComponentId]T),
+		mux:   &sync.RWMutex{},
+	}
+}
+
+// Set adds the item, replacing the one with the same id
+func (registry *Registry[T]) Set(id ComponentId, item T) {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+
+	registry.items[id] = item
+}
+
+// Add adds the item when there isn't one with the same id
+func (registry *Registry[T]) Add(id ComponentId, item T) bool {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+
+	if _, exists := registry.items[id]; exists {
+		return false
+	}
+	registry.items[id] = item
+
+	return true
+}
+
+// Get ...
+func (registry *Registry[T]) Get(id ComponentId) (T, bool) {
+	registry.mux.RLock()
+	defer registry.mux.RUnlock()
+
+	item, exists := registry.items[id]
+	return item, exists
+}
+
+// Remove ...
+func (registry *Registry[T]) Remove(id ComponentId) (T, bool) {
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+
+	item, exists := registry.items[id]
+	delete(registry.items, id)
+
+	return item, exists
+}
+
+// Ids returns the sorted ids of the items of the given kinds, or of every kind when none is given
+func (registry *Registry[T]) Ids(kinds ...Kind) []ComponentId {
+	registry.mux.RLock()
+	defer registry.mux.RUnlock()
+
+	ids := make([]ComponentId, 0, len(registry.items))
+	for id := range registry.items {
+		if len(kinds) == 0 || containsKind(kinds, id.Kind) {
+			ids = append(ids, id)
+		}
+	}
+	sortComponentIds(ids)
+
+	return ids
+}
+
+// Items returns a copy of the items of the given kinds, or of every kind when none is given
+func (registry *Registry[T]) Items(kinds ...Kind) map[ComponentId]T {
+	registry.mux.RLock()
+	defer registry.mux.RUnlock()
+
+	items := make(map[ComponentId]T, len(registry.items))
+	for id, item := range registry.items {
+		if len(kinds) == 0 || containsKind(kinds, id.Kind) {
+			items[id] = item
+		}
+	}
+
+	return items
+}
+
+// Get returns the component with the given key that is a T, from the kind of T when it's a known one,
+// or from the first kind with a T otherwise
+func Get[T any](manager *Manager, key string) T {
+	if kind, exists := kindOf[T](); exists {
+		component, _ := GetComponent[T](manager, kind, key)
+		return component
+	}
+
+	for _, id := range manager.registry.Ids() {
+		if id.Key != key {
+			continue
+		}
+
+		if component, ok := GetComponent[T](manager, id.Kind, key); ok {
+			return component
+		}
+	}
+
+	var zero T
+	return zero
+}
+
+// GetComponent returns the component with the given kind and key, when it's a T
+func GetComponent[T any](manager *Manager, kind Kind, key string) (T, bool) {
+	var zero T
+
+	item, exists := manager.registry.Get(NewComponentId(kind, key))
+	if !exists {
+		return zero, false
+	}
+
+	component, ok := item.(T)
+	if !ok {
+		return zero, false
+	}
+
+	return component, true
+}
+
+// ComponentInfo ...
+type ComponentInfo struct {
+	Kind      Kind           `json:"kind"`
+	Key       string         `json:"key"`
+	State     ComponentState `json:"state"`
+	Component interface{}    `json:"-"`
+}
+
+// Components returns the components of the given kinds, or of every kind when none is given,
+// sorted by kind and key
+func (manager *Manager) Components(kinds ...Kind) []*ComponentInfo {
+	items := manager.registry.Items(kinds...)
+
+	ids := make([]ComponentId, 0, len(items))
+	for id := range items {
+		ids = append(ids, id)
+	}
+	sortComponentIds(ids)
+
+	infos := make([]*ComponentInfo, 0, len(items))
+	for _, id := range ids {
+		infos = append(infos, &ComponentInfo{
+			Kind:      id.Kind,
+			Key:       id.Key,
+			State:     manager.State(id.Kind, id.Key),
+			Component: items[id],
+		})
+	}
+
+	return infos
+}
+
+// kindOf returns the known kind with the interface T
+func kindOf[T any]() (Kind, bool) {
+	componentType := reflect.TypeOf((*T)(nil)).Elem()
+	for kind, kindType := range kindTypes {
+		if kindType == componentType {
+			return kind, true
+		}
+	}
+
+	return "", false
+}
+
+// canBeKind checks if the component has the interface of the kind, where any component can be of a custom kind
+func canBeKind(kind Kind, component interface{}) bool {
+	kindType, exists := kindTypes[kind]
+	if !exists {
+		return true
+	}
+
+	return component != nil && reflect.TypeOf(component).Implements(kindType)
+}
+
+func containsKind(kinds []Kind, kind Kind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
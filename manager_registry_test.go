@@ -0,0 +1,54 @@
+package manager
+
+import "testing"
+
+func TestRegistry(t *testing.T) {
+	manager := newTestManager()
+
+	process := manager.NewSimpleProcess(func() error { return nil })
+	if err := manager.AddProcess("main", process); err != nil {
+		t.Fatal(err)
+	}
+
+	custom := manager.NewSimpleProcess(func() error { return nil })
+	if err := manager.Register("custom", "main", custom); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Register(KindDB, "main", process); err == nil {
+		t.Fatal("a process was registered as a database")
+	}
+
+	if got := Get[IProcess](manager, "main"); got != process {
+		t.Fatalf("expected the process, got %v", got)
+	}
+
+	if got, ok := GetComponent[*SimpleProcess](manager, "custom", "main"); !ok || got != custom {
+		t.Fatalf("expected the custom component, got %v", got)
+	}
+
+	if got := Get[IDB](manager, "main"); got != nil {
+		t.Fatalf("expected no database, got %v", got)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Stop()
+
+	components := manager.Components()
+	if len(components) != 2 {
+		t.Fatalf("expected 2 components, got %d", len(components))
+	}
+
+	// the components of custom kinds are the last ones
+	if components[0].Kind != KindProcess || components[1].Kind != "custom" {
+		t.Fatalf("unexpected order [ %s, %s ]", components[0].Kind, components[1].Kind)
+	}
+
+	for _, component := range components {
+		if component.State != StateRunning {
+			t.Fatalf("the component [ %s: %s ] is %s", component.Kind, component.Key, component.State)
+		}
+	}
+}
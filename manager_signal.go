@@ -0,0 +1,199 @@
+package manager
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime/pprof"
+	"syscall"
+
+	"github.com/joaosoft/logger"
+)
+
+// SignalAction is what the manager does when it receives a signal
+type SignalAction string
+
+const (
+	// SignalGracefulStop drains and stops the components
+	SignalGracefulStop SignalAction = "graceful_stop"
+	// SignalImmediateStop stops the components without draining them
+	SignalImmediateStop SignalAction = "immediate_stop"
+	// SignalReload reloads every registered config
+	SignalReload SignalAction = "reload"
+	// SignalToggleLogLevel switches the log level between debug and the configured one
+	SignalToggleLogLevel SignalAction = "toggle_log_level"
+	// SignalDump writes the components, the work lists queues and the goroutine stacks
+	SignalDump SignalAction = "dump"
+	// SignalIgnore does nothing, besides calling the handlers of the signal
+	SignalIgnore SignalAction = "ignore"
+)
+
+// SignalHandler is called when the manager receives a signal, after its action
+type SignalHandler func(sig os.Signal)
+
+// IDumpable is implemented by the components that can dump their content, as the work lists
+type IDumpable interface {
+	Dump() string
+}
+
+// DefaultSignalPolicy returns the actions of the signals when none is configured
+func DefaultSignalPolicy() map[os.Signal]SignalAction {
+	return map[os.Signal]SignalAction{
+		syscall.SIGINT:  SignalGracefulStop,
+		syscall.SIGTERM: SignalGracefulStop,
+		syscall.SIGHUP:  SignalReload,
+		syscall.SIGUSR1: SignalDump,
+		syscall.SIGUSR2: SignalToggleLogLevel,
+	}
+}
+
+// OnSignal sets the action of the signal
+func (manager *Manager) OnSignal(sig os.Signal, action SignalAction) {
+	manager.mux.Lock()
+	defer manager.mux.Unlock()
+
+	manager.signalPolicy[sig] = action
+}
+
+// HandleSignal adds a handler of the signal, called after its action
+func (manager *Manager) HandleSignal(sig os.Signal, handler SignalHandler) {
+	manager.mux.Lock()
+	defer manager.mux.Unlock()
+
+	manager.signalHandlers[sig] = append(manager.signalHandlers[sig], handler)
+}
+
+// signals returns the signals with an action or a handler
+func (manager *Manager) signals() []os.Signal {
+	manager.mux.RLock()
+	defer manager.mux.RUnlock()
+
+	var signals []os.Signal
+	for sig := range manager.signalPolicy {
+		signals = append(signals, sig)
+	}
+
+	for sig := range manager.signalHandlers {
+		if _, exists := manager.signalPolicy[sig]; !exists {
+			signals = append(signals, sig)
+		}
+	}
+
+	return signals
+}
+
+// handleSignal executes the action and the handlers of the signal, returning the action
+func (manager *Manager) handleSignal(sig os.Signal) SignalAction {
+	manager.mux.RLock()
+	action, exists := manager.signalPolicy[sig]
+	handlers := make([]SignalHandler, len(manager.signalHandlers[sig]))
+	copy(handlers, manager.signalHandlers[sig])
+	manager.mux.RUnlock()
+
+	if !exists {
+		action = SignalIgnore
+	}
+
+	manager.logger.Infof("received signal [ signal: %s, action: %s ]", sig, action)
+
+	switch action {
+	case SignalReload:
+		if err := manager.ReloadConfigs(); err != nil {
+			manager.logger.Errorf("error reloading the configs [ %s ]", err)
+		}
+	case SignalToggleLogLevel:
+		manager.ToggleDebug()
+	case SignalDump:
+		if err := manager.Dump(manager.dumpWriter); err != nil {
+			manager.logger.Errorf("error dumping the manager: %s", err)
+		}
+	}
+
+	for _, handler := range handlers {
+		handler(sig)
+	}
+
+	return action
+}
+
+// ReloadConfigs reloads every registered config, returning the errors of the ones that failed
+func (manager *Manager) ReloadConfigs() error {
+	errs := make(ComponentErrors)
+	for id, component := range manager.registry.Items(KindConfig) {
+		config, ok := component.(IConfig)
+		if !ok {
+			continue
+		}
+
+		if err := config.Reload(); err != nil {
+			errs.Add(id, "reload", err)
+			continue
+		}
+
+		manager.logger.Infof("config %s reloaded", id.Key)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// LogLevel returns the log level of the manager
+func (manager *Manager) LogLevel() logger.Level {
+	manager.mux.RLock()
+	defer manager.mux.RUnlock()
+
+	if manager.debug {
+		return logger.DebugLevel
+	}
+
+	return manager.logLevel
+}
+
+// SetLogLevel changes the log level of the manager
+func (manager *Manager) SetLogLevel(level logger.Level) {
+	manager.mux.Lock()
+	manager.logLevel = level
+	manager.debug = false
+	manager.mux.Unlock()
+
+	manager.logger.SetLevel(level)
+}
+
+// ToggleDebug switches the log level between debug and the configured one
+func (manager *Manager) ToggleDebug() {
+	manager.mux.Lock()
+	manager.debug = !manager.debug
+	level := manager.logLevel
+	if manager.debug {
+		level = logger.DebugLevel
+	}
+	manager.mux.Unlock()
+
+	manager.logger.SetLevel(level)
+	manager.logger.Infof("log level set to %s", level)
+}
+
+// Dump writes the states of the components, the content of the dumpable ones, as the work lists queues,
+// and the stacks of the goroutines
+func (manager *Manager) Dump(writer io.Writer) error {
+	if _, err := fmt.Fprintln(writer, "components:"); err != nil {
+		return err
+	}
+
+	for _, info := range manager.Components() {
+		fmt.Fprintf(writer, "  [ %s ] %s\n", NewComponentId(info.Kind, info.Key), info.State)
+
+		if dumpable, ok := info.Component.(IDumpable); ok {
+			fmt.Fprintf(writer, "%s\n", dumpable.Dump())
+		}
+	}
+
+	if _, err := fmt.Fprintln(writer, "goroutines:"); err != nil {
+		return err
+	}
+
+	return pprof.Lookup("goroutine").WriteTo(writer, 2)
+}
@@ -0,0 +1,75 @@
+package manager
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/joaosoft/logger"
+)
+
+func TestSignalPolicy(t *testing.T) {
+	var dump bytes.Buffer
+	manager := NewManager(WithRunInBackground(true), WithBackgroundSignals(true), WithDumpWriter(&dump),
+		WithSignalPolicy(map[os.Signal]SignalAction{
+			syscall.SIGUSR1: SignalDump,
+			syscall.SIGUSR2: SignalToggleLogLevel,
+			syscall.SIGTERM: SignalImmediateStop,
+		}))
+
+	handled := make(chan os.Signal, 3)
+	for _, sig := range []os.Signal{syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGTERM} {
+		manager.HandleSignal(sig, func(sig os.Signal) { handled <- sig })
+	}
+
+	worklist := manager.NewSimpleWorkList(NewWorkListConfig("queue", 1, 1, time.Millisecond, FIFO),
+		func(id string, data interface{}) error { return nil }, nil, nil)
+	if err := manager.AddWorkList("queue", worklist); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sig := range []syscall.Signal{syscall.SIGUSR1, syscall.SIGUSR2} {
+		if err := syscall.Kill(os.Getpid(), sig); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case <-handled:
+		case <-time.After(time.Second):
+			t.Fatalf("expected the signal %s to be handled", sig)
+		}
+	}
+
+	if !strings.Contains(dump.String(), "[ worklist: queue ] running") || !strings.Contains(dump.String(), "goroutine") {
+		t.Fatalf("unexpected dump %s", dump.String())
+	}
+
+	if level := manager.LogLevel(); level != logger.DebugLevel {
+		t.Fatalf("expected the debug log level, got %d", level)
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-handled:
+	case <-time.After(time.Second):
+		t.Fatal("expected the stop signal to be handled")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for manager.Started() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the manager to be stopped")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
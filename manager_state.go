@@ -0,0 +1,143 @@
+package manager
+
+import (
+	"time"
+)
+
+// ComponentState ...
+type ComponentState string
+
+const (
+	// StateRegistered is the state of the components added and not started yet, and of the ones without a lifecycle
+	StateRegistered ComponentState = "registered"
+	StateStarting   ComponentState = "starting"
+	StateRunning    ComponentState = "running"
+	// StateDegraded is the state of the running components that failed their health check
+	StateDegraded ComponentState = "degraded"
+	StateStopping ComponentState = "stopping"
+	StateStopped  ComponentState = "stopped"
+	StateFailed   ComponentState = "failed"
+)
+
+// ComponentEvent is a transition of the state of a component
+type ComponentEvent struct {
+	Id   ComponentId
+	From ComponentState
+	To   ComponentState
+	Err  error
+	Time time.Time
+}
+
+// OnEvent subscribes the handler to the transitions of the states of the components.
+// the handlers are called in the order they were added, on the goroutine that changed the state
+func (manager *Manager) OnEvent(handler func(event ComponentEvent)) {
+	manager.mux.Lock()
+	defer manager.mux.Unlock()
+
+	manager.eventHandlers = append(manager.eventHandlers, handler)
+}
+
+// State returns the state of the component with the given kind and key, or empty when it doesn't exist
+func (manager *Manager) State(kind Kind, key string) ComponentState {
+	manager.mux.RLock()
+	defer manager.mux.RUnlock()
+
+	return manager.states[NewComponentId(kind, key)]
+}
+
+// setState changes the state of the component, when it's in one of the given states or in any state when none is given
+func (manager *Manager) setState(id ComponentId, to ComponentState, err error, from ...ComponentState) bool {
+	manager.mux.Lock()
+	previous := manager.states[id]
+	if previous == to || (len(from) > 0 && !containsState(from, previous)) {
+		manager.mux.Unlock()
+		return false
+	}
+
+	manager.states[id] = to
+	handlers := make([]func(event ComponentEvent), len(manager.eventHandlers))
+	copy(handlers, manager.eventHandlers)
+	manager.mux.Unlock()
+
+	if err != nil {
+		manager.logger.Errorf("component [ %s ] %s -> %s: %s", id, previous, to, err)
+	} else {
+		manager.logger.Infof("component [ %s ] %s -> %s", id, previous, to)
+	}
+
+	event := ComponentEvent{
+		Id:   id,
+		From: previous,
+		To:   to,
+		Err:  err,
+		Time: time.Now(),
+	}
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+
+	return true
+}
+
+// removeState forgets the state of a removed component
+func (manager *Manager) removeState(id ComponentId) {
+	manager.mux.Lock()
+	defer manager.mux.Unlock()
+
+	delete(manager.states, id)
+	delete(manager.registrations, id)
+}
+
+// setHealth changes the state of a running component to degraded when it isn't healthy, and back to running
+func (manager *Manager) setHealth(id ComponentId, err error) {
+	if err != nil {
+		manager.setState(id, StateDegraded, err, StateRunning)
+	} else {
+		manager.setState(id, StateRunning, nil, StateDegraded)
+	}
+}
+
+func containsState(states []ComponentState, state ComponentState) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StateReporter reports a transition of the state of a component
+type StateReporter func(state ComponentState, err error)
+
+// IStateReporter is implemented by the components that change their state on their own, as the supervised processes,
+// receiving the reporter of their state when they are added
+type IStateReporter interface {
+	SetStateReporter(reporter StateReporter)
+}
+
+// attach gives the reporter of its state to the component, reporting only while the component is registered,
+// where each registration has its own token, so the components don't have to be comparable
+func (manager *Manager) attach(id ComponentId, component interface{}) {
+	manager.mux.Lock()
+	manager.lastRegistration++
+	registration := manager.lastRegistration
+	manager.registrations[id] = registration
+	manager.mux.Unlock()
+
+	stateReporter, ok := component.(IStateReporter)
+	if !ok {
+		return
+	}
+
+	stateReporter.SetStateReporter(func(state ComponentState, err error) {
+		manager.mux.RLock()
+		current := manager.registrations[id]
+		manager.mux.RUnlock()
+
+		if current == registration {
+			manager.setState(id, state, err)
+		}
+	})
+}
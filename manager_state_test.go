@@ -0,0 +1,114 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestEvents(t *testing.T) {
+	manager := newTestManager()
+
+	var mux sync.Mutex
+	var events []ComponentEvent
+	manager.OnEvent(func(event ComponentEvent) {
+		mux.Lock()
+		defer mux.Unlock()
+		events = append(events, event)
+	})
+
+	if err := manager.AddProcess("process", manager.NewSimpleProcess(func() error { return nil })); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.AddProcess("failing", manager.NewSimpleProcess(func() error { return fmt.Errorf("failed") })); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Start(); err == nil {
+		t.Fatal("expected the failing process to fail to start")
+	}
+
+	if state := manager.State(KindProcess, "failing"); state != StateFailed {
+		t.Fatalf("expected the failing process to be failed, got %s", state)
+	}
+
+	if err := manager.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	var transitions []ComponentState
+	mux.Lock()
+	for _, event := range events {
+		if event.Id.Key == "process" {
+			transitions = append(transitions, event.To)
+		}
+	}
+	mux.Unlock()
+
+	expected := []ComponentState{StateRegistered, StateStarting, StateRunning, StateStopping, StateStopped}
+	if fmt.Sprint(transitions) != fmt.Sprint(expected) {
+		t.Fatalf("expected the transitions %v, got %v", expected, transitions)
+	}
+}
+
+// testReportingComponent is a component that reports its own state, that isn't comparable as it's kept by value with a slice
+type testReportingComponent struct {
+	reporter *StateReporter
+	state    *lifecycleState
+	tags     []string
+}
+
+func newTestReportingComponent() testReportingComponent {
+	return testReportingComponent{reporter: new(StateReporter), state: &lifecycleState{}}
+}
+
+func (component testReportingComponent) Start(ctx context.Context) error {
+	component.state.setStarted(true)
+	return nil
+}
+
+func (component testReportingComponent) Stop(ctx context.Context) error {
+	component.state.setStarted(false)
+	return nil
+}
+
+func (component testReportingComponent) Started() bool {
+	return component.state.isStarted()
+}
+
+func (component testReportingComponent) SetStateReporter(reporter StateReporter) {
+	*component.reporter = reporter
+}
+
+func TestStateReporter(t *testing.T) {
+	manager := newTestManager()
+
+	first := newTestReportingComponent()
+	if err := manager.Register("store", "cache", first); err != nil {
+		t.Fatal(err)
+	}
+
+	(*first.reporter)(StateDegraded, fmt.Errorf("slow"))
+	if state := manager.State("store", "cache"); state != StateDegraded {
+		t.Fatalf("expected the reported state, got %s", state)
+	}
+
+	// the replaced and the removed components don't report anymore
+	second := newTestReportingComponent()
+	if err := manager.ReplaceComponent("store", "cache", second); err != nil {
+		t.Fatal(err)
+	}
+	(*first.reporter)(StateFailed, fmt.Errorf("failed"))
+	if state := manager.State("store", "cache"); state != StateRegistered {
+		t.Fatalf("expected the state of the new component, got %s", state)
+	}
+
+	if _, err := manager.Unregister("store", "cache"); err != nil {
+		t.Fatal(err)
+	}
+	(*second.reporter)(StateFailed, fmt.Errorf("failed"))
+	if state := manager.State("store", "cache"); state != "" {
+		t.Fatalf("expected the removed component not to report, got %s", state)
+	}
+}
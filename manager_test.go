@@ -0,0 +1,223 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestManager() *Manager {
+	return NewManager(WithRunInBackground(true))
+}
+
+func TestConcurrentStartStop(t *testing.T) {
+	manager := newTestManager()
+
+	for i := 0; i < 3; i++ {
+		key := fmt.Sprintf("queue_%d", i)
+		worklist := manager.NewSimpleWorkList(NewWorkListConfig(key, 2, 1, time.Millisecond, FIFO),
+			func(id string, data interface{}) error { return nil }, nil, nil)
+
+		if err := manager.AddWorkList(key, worklist); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(4)
+
+		go func() {
+			defer wg.Done()
+			if err := manager.Start(); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			if err := manager.Stop(); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		go func(i int) {
+			defer wg.Done()
+
+			key := fmt.Sprintf("process_%d", i)
+			if err := manager.AddProcess(key, manager.NewSimpleProcess(func() error { return nil })); err != nil {
+				t.Error(err)
+			}
+			if _, err := manager.RemoveProcess(key); err != nil {
+				t.Error(err)
+			}
+		}(i)
+
+		go func(i int) {
+			defer wg.Done()
+
+			manager.GetWorkList(fmt.Sprintf("queue_%d", i%3)).AddWork(fmt.Sprintf("work_%d", i), i)
+			manager.Readiness(context.Background())
+			if err := manager.Metrics().Write(ioutil.Discard); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := manager.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	if manager.Started() {
+		t.Fatal("the manager is started after being stopped")
+	}
+}
+
+// testDrainable is a component that tells if the context of the manager was canceled while it was draining
+type testDrainable struct {
+	manager  *Manager
+	canceled bool
+	state    lifecycleState
+}
+
+func (component *testDrainable) Start(ctx context.Context) error {
+	component.state.setStarted(true)
+	return nil
+}
+
+func (component *testDrainable) Stop(ctx context.Context) error {
+	component.state.setStarted(false)
+	return nil
+}
+
+func (component *testDrainable) Started() bool {
+	return component.state.isStarted()
+}
+
+func (component *testDrainable) Drain(ctx context.Context) error {
+	component.canceled = component.manager.Context().Err() != nil
+	return nil
+}
+
+func TestStopCancelsAfterDrain(t *testing.T) {
+	manager := newTestManager()
+	component := &testDrainable{manager: manager}
+	if err := manager.Register("drainable", "main", component); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+	ctx := manager.Context()
+
+	if err := manager.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	if component.canceled {
+		t.Fatal("expected the context of the manager to be canceled after the drain")
+	}
+	if ctx.Err() == nil {
+		t.Fatal("expected the context of the manager to be canceled after the stop")
+	}
+
+	// the next start takes a new context
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Stop()
+
+	if manager.Context().Err() != nil {
+		t.Fatal("expected a new context on the next start")
+	}
+}
+
+// testDrainingComponent is a test component that records its drain, blocking it until its context is done when asked
+type testDrainingComponent struct {
+	*testComponent
+	blockDrain bool
+}
+
+func (component testDrainingComponent) Drain(ctx context.Context) error {
+	component.calls.add("drain " + component.name)
+
+	if component.blockDrain {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+func TestDrain(t *testing.T) {
+	for _, immediately := range []bool{false, true} {
+		t.Run(fmt.Sprintf("immediately %t", immediately), func(t *testing.T) {
+			manager := newTestManager()
+			calls := &testCalls{}
+
+			if err := manager.Register(KindProcess, "worker", testDrainingComponent{testComponent: &testComponent{name: "worker", calls: calls}}); err != nil {
+				t.Fatal(err)
+			}
+			if err := manager.Register("store", "cache", testDrainingComponent{testComponent: &testComponent{name: "cache", calls: calls}}); err != nil {
+				t.Fatal(err)
+			}
+			if err := manager.AddDependency(KindProcess, "worker", NewComponentId("store", "cache")); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := manager.Start(); err != nil {
+				t.Fatal(err)
+			}
+
+			stop := manager.Stop
+			expected := []string{"start cache", "start worker", "drain worker", "drain cache", "stop worker", "stop cache"}
+			if immediately {
+				stop = manager.StopImmediately
+				expected = []string{"start cache", "start worker", "stop worker", "stop cache"}
+			}
+
+			if err := stop(); err != nil {
+				t.Fatal(err)
+			}
+
+			// every component is drained before the first one is stopped, in the stop order
+			if got := calls.get(); fmt.Sprint(got) != fmt.Sprint(expected) {
+				t.Fatalf("expected the calls %v, got %v", expected, got)
+			}
+		})
+	}
+}
+
+func TestDrainTimeout(t *testing.T) {
+	manager := NewManager(WithRunInBackground(true), WithDrainTimeout(10*time.Millisecond))
+	calls := &testCalls{}
+
+	component := testDrainingComponent{testComponent: &testComponent{name: "worker", calls: calls}, blockDrain: true}
+	if err := manager.Register(KindProcess, "worker", component); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the component is stopped when the drain phase times out, reporting the error of the drain
+	start := time.Now()
+	err := manager.Stop()
+	if errs, ok := err.(ComponentErrors); !ok || !errors.Is(err, context.DeadlineExceeded) || errs[NewComponentId(KindProcess, "worker")].Action != "drain" {
+		t.Fatalf("expected the drain deadline to be exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the drain to be cut on the drain timeout, took %s", elapsed)
+	}
+
+	if component.Started() || manager.State(KindProcess, "worker") != StateStopped {
+		t.Fatal("expected the component to be stopped after the drain timeout")
+	}
+}
@@ -1,6 +1,9 @@
 package manager
 
-import "sync"
+import (
+	"context"
+	"net/http"
+)
 
 type HandlerFunc interface{}
 type MiddlewareFunc interface{}
@@ -21,15 +24,23 @@ type IWeb interface {
 	AddRoutes(routes ...*Route) error
 	AddNamespace(path string, middleware []MiddlewareFunc, routes ...*Route) error
 	AddFilter(pattern string, position string, middleware MiddlewareFunc, method string, methods ...string)
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
 	Started() bool
 	GetClient() interface{}
 }
 
+// IHttpHandlerWeb is implemented by the webs that can serve a standard http.Handler
+type IHttpHandlerWeb interface {
+	AddHttpHandler(method, path string, handler http.Handler) error
+}
+
 // AddWeb ...
 func (manager *Manager) AddWeb(key string, web IWeb) error {
-	manager.webs[key] = web
+	if err := manager.add(KindWeb, key, web); err != nil {
+		return err
+	}
+
 	manager.logger.Infof("web %s added", key)
 
 	return nil
@@ -37,17 +48,17 @@ func (manager *Manager) AddWeb(key string, web IWeb) error {
 
 // RemoveWeb ...
 func (manager *Manager) RemoveWeb(key string) (IWeb, error) {
-	web := manager.webs[key]
+	component, err := manager.remove(KindWeb, key)
+	web, _ := component.(IWeb)
 
-	delete(manager.webs, key)
 	manager.logger.Infof("web %s removed", key)
 
-	return web, nil
+	return web, err
 }
 
 // GetWeb ...
 func (manager *Manager) GetWeb(key string) IWeb {
-	if web, ok := manager.webs[key]; ok {
+	if web, ok := GetComponent[IWeb](manager, KindWeb, key); ok {
 		return web
 	}
 	manager.logger.Infof("web %s doesn't exist", key)
@@ -1,13 +1,13 @@
 package manager
 
 import (
-	"sync"
+	"context"
 	"time"
 )
 
 type IWorkList interface {
-	Start(waitGroup ...*sync.WaitGroup) error
-	Stop(waitGroup ...*sync.WaitGroup) error
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
 	Started() bool
 	AddWork(id string, work interface{})
 }
@@ -15,10 +15,12 @@ type IWorkList interface {
 // WorkListConfig ...
 type WorkListConfig struct {
 	Name       string        `json:"name"`
-	MaxWorkers int           `json:"max_workers"`
-	MaxRetries int           `json:"max_retries"`
+	MaxWorkers int           `json:"max_workers" validate:"min=1"`
+	MaxRetries int           `json:"max_retries" validate:"min=0"`
 	SleepTime  time.Duration `json:"sleep_time"`
 	Mode       Mode          `json:"mode"`
+	// HealthThreshold is the list size above which the work list isn't healthy, where zero disables the check
+	HealthThreshold int `json:"health_threshold"`
 }
 
 // NewWorkListConfig...
@@ -35,11 +37,13 @@ func NewWorkListConfig(name string, maxWorkers, maxRetries int, sleepTime time.D
 // BulkWorkListConfig ...
 type BulkWorkListConfig struct {
 	Name       string        `json:"name"`
-	MaxWorks   int           `json:"max_works"`
-	MaxWorkers int           `json:"max_workers"`
-	MaxRetries int           `json:"max_retries"`
+	MaxWorks   int           `json:"max_works" validate:"min=1"`
+	MaxWorkers int           `json:"max_workers" validate:"min=1"`
+	MaxRetries int           `json:"max_retries" validate:"min=0"`
 	SleepTime  time.Duration `json:"sleep_time"`
 	Mode       Mode          `json:"mode"`
+	// HealthThreshold is the list size above which the work list isn't healthy, where zero disables the check
+	HealthThreshold int `json:"health_threshold"`
 }
 
 // NewBulkWorkListConfig...
@@ -56,7 +60,10 @@ func NewBulkWorkListConfig(name string, maxWorks, maxWorkers, maxRetries int, sl
 
 // AddWorkList ...
 func (manager *Manager) AddWorkList(key string, worklist IWorkList) error {
-	manager.worklist[key] = worklist
+	if err := manager.add(KindWorkList, key, worklist); err != nil {
+		return err
+	}
+
 	manager.logger.Infof("work list %s added", key)
 
 	return nil
@@ -64,17 +71,17 @@ func (manager *Manager) AddWorkList(key string, worklist IWorkList) error {
 
 // RemoveWorkList ...
 func (manager *Manager) RemoveWorkList(key string) (IWorkList, error) {
-	list := manager.worklist[key]
+	component, err := manager.remove(KindWorkList, key)
+	list, _ := component.(IWorkList)
 
-	delete(manager.worklist, key)
 	manager.logger.Infof("work list %s removed", key)
 
-	return list, nil
+	return list, err
 }
 
 // GetWorkList ...
 func (manager *Manager) GetWorkList(key string) IWorkList {
-	if list, exists := manager.worklist[key]; exists {
+	if list, exists := GetComponent[IWorkList](manager, KindWorkList, key); exists {
 		return list
 	}
 	manager.logger.Infof("work list %s doesn't exist", key)
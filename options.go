@@ -1,6 +1,12 @@
 package manager
 
-import "github.com/joaosoft/logger"
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/joaosoft/logger"
+)
 
 // ManagerOption ...
 type ManagerOption func(manager *Manager)
@@ -31,6 +37,7 @@ func WithLogger(logger logger.ILogger) ManagerOption {
 func WithLogLevel(level logger.Level) ManagerOption {
 	return func(manager *Manager) {
 		manager.logger.SetLevel(level)
+		manager.logLevel = level
 	}
 }
 
@@ -40,3 +47,74 @@ func WithQuitChannel(quit chan int) ManagerOption {
 		manager.quit = quit
 	}
 }
+
+// WithRollbackOnStartError stops the already started components when any component fails to start
+func WithRollbackOnStartError(rollback bool) ManagerOption {
+	return func(manager *Manager) {
+		manager.rollbackOnStartError = rollback
+	}
+}
+
+// WithTimeouts sets the default deadlines of the lifecycle of the components
+func WithTimeouts(timeouts *Timeouts) ManagerOption {
+	return func(manager *Manager) {
+		if timeouts != nil {
+			manager.defaultTimeouts = timeouts
+		}
+	}
+}
+
+// WithDrainTimeout sets the deadline of the whole drain phase, where the components finish the work in flight before being stopped
+func WithDrainTimeout(timeout time.Duration) ManagerOption {
+	return func(manager *Manager) {
+		manager.drainTimeout = timeout
+	}
+}
+
+// WithHealthTimeout sets the deadline of the health checks of the health handlers
+func WithHealthTimeout(timeout time.Duration) ManagerOption {
+	return func(manager *Manager) {
+		manager.healthTimeout = timeout
+	}
+}
+
+// WithHealthInterval sets the interval of the background health checks that change the state of the running components
+// to degraded and back to running, where a non-positive interval disables them
+func WithHealthInterval(interval time.Duration) ManagerOption {
+	return func(manager *Manager) {
+		manager.healthInterval = interval
+	}
+}
+
+// WithMetrics sets the metrics registry of the manager, to share it with the application
+func WithMetrics(metrics *Metrics) ManagerOption {
+	return func(manager *Manager) {
+		if metrics != nil {
+			manager.metrics = metrics
+		}
+	}
+}
+
+// WithSignalPolicy replaces the actions of the signals, where the signals without an action are ignored
+func WithSignalPolicy(policy map[os.Signal]SignalAction) ManagerOption {
+	return func(manager *Manager) {
+		manager.signalPolicy = make(map[os.Signal]SignalAction, len(policy))
+		for sig, action := range policy {
+			manager.signalPolicy[sig] = action
+		}
+	}
+}
+
+// WithBackgroundSignals listens for the signals of the signal policy when running in background too
+func WithBackgroundSignals(enabled bool) ManagerOption {
+	return func(manager *Manager) {
+		manager.backgroundSignals = enabled
+	}
+}
+
+// WithDumpWriter sets where the dumps of the signals are written, the standard error by default
+func WithDumpWriter(writer io.Writer) ManagerOption {
+	return func(manager *Manager) {
+		manager.dumpWriter = writer
+	}
+}
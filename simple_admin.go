@@ -0,0 +1,403 @@
+package manager
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/joaosoft/logger"
+)
+
+// AdminAuth authorizes the requests to the admin api, failing for the ones that aren't allowed
+type AdminAuth func(r *http.Request) error
+
+// AdminRoute ...
+type AdminRoute struct {
+	Method  string
+	Path    string
+	Handler http.Handler
+}
+
+// logLevels are the names of the log levels accepted by the admin api
+var logLevels = map[string]logger.Level{
+	"panic": logger.PanicLevel,
+	"fatal": logger.FatalLevel,
+	"error": logger.ErrorLevel,
+	"warn":  logger.WarnLevel,
+	"info":  logger.InfoLevel,
+	"debug": logger.DebugLevel,
+}
+
+// Admin exposes the components of the manager over http, to inspect and control them.
+// it's mounted on a web of the manager, or it's added as a process to serve on its own address
+type Admin struct {
+	manager *Manager
+	prefix  string
+	address string
+	auth    AdminAuth
+	server  *http.Server
+	logger  logger.ILogger
+	state   lifecycleState
+}
+
+// NewAdmin ...
+func (manager *Manager) NewAdmin(options ...AdminOption) *Admin {
+	admin := &Admin{
+		manager: manager,
+		prefix:  "/admin",
+		logger:  manager.logger,
+	}
+	admin.Reconfigure(options...)
+
+	return admin
+}
+
+// AddAdminRoutes mounts the routes of the admin api on the web with the given key
+func (manager *Manager) AddAdminRoutes(key string, admin *Admin) error {
+	web, err := manager.getHttpHandlerWeb(key)
+	if err != nil {
+		return err
+	}
+
+	for _, route := range admin.Routes() {
+		if err := web.AddHttpHandler(route.Method, route.Path, route.Handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Start serves the admin api on its address
+func (admin *Admin) Start(ctx context.Context) error {
+	admin.state.lock()
+	defer admin.state.unlock()
+
+	if admin.state.isStarted() {
+		return nil
+	}
+
+	if admin.address == "" {
+		return fmt.Errorf("admin, no address configured")
+	}
+
+	listener, err := net.Listen("tcp", admin.address)
+	if err != nil {
+		return admin.logger.Errorf("admin, error listening on %s: %s", admin.address, err).ToError()
+	}
+
+	admin.server = &http.Server{Handler: admin.Handler()}
+	go func(server *http.Server) {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			admin.logger.Errorf("admin, error serving on %s: %s", admin.address, err)
+		}
+	}(admin.server)
+
+	admin.logger.Infof("admin listening on %s", listener.Addr())
+	admin.state.setStarted(true)
+
+	return nil
+}
+
+// Stop ...
+func (admin *Admin) Stop(ctx context.Context) error {
+	admin.state.lock()
+	defer admin.state.unlock()
+
+	if !admin.state.isStarted() {
+		return nil
+	}
+
+	if err := admin.server.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	admin.state.setStarted(false)
+
+	return nil
+}
+
+// Started ...
+func (admin *Admin) Started() bool {
+	return admin.state.isStarted()
+}
+
+// Handler returns a handler with every route of the admin api
+func (admin *Admin) Handler() http.Handler {
+	routes := make(map[string]map[string]http.Handler)
+	for _, route := range admin.Routes() {
+		if routes[route.Path] == nil {
+			routes[route.Path] = make(map[string]http.Handler)
+		}
+		routes[route.Path][route.Method] = route.Handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods, exists := routes[r.URL.Path]
+		if !exists {
+			writeAdminError(w, http.StatusNotFound, fmt.Errorf("route %s not found", r.URL.Path))
+			return
+		}
+
+		handler, exists := methods[r.Method]
+		if !exists {
+			writeAdminError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// Routes returns the routes of the admin api, with the authorization of the requests
+func (admin *Admin) Routes() []*AdminRoute {
+	routes := []*AdminRoute{
+		{http.MethodGet, "/status", admin.handle(admin.status)},
+		{http.MethodGet, "/components", admin.handle(admin.components)},
+		{http.MethodPost, "/components/start", admin.handle(admin.control(admin.manager.StartComponent))},
+		{http.MethodPost, "/components/stop", admin.handle(admin.control(admin.manager.StopComponent))},
+		{http.MethodPost, "/components/restart", admin.handle(admin.control(admin.manager.RestartComponent))},
+		{http.MethodPost, "/components/pause", admin.handle(admin.control(admin.manager.PauseComponent))},
+		{http.MethodPost, "/components/resume", admin.handle(admin.control(admin.manager.ResumeComponent))},
+		{http.MethodGet, "/worklists/dump", admin.handle(admin.dump)},
+		{http.MethodPost, "/worklists/drain", admin.handle(admin.drain)},
+		{http.MethodGet, "/configs/get", admin.handle(admin.config)},
+		{http.MethodPost, "/configs/reload", admin.handle(admin.reload)},
+		{http.MethodGet, "/log/level", admin.handle(admin.logLevel)},
+		{http.MethodPut, "/log/level", admin.handle(admin.setLogLevel)},
+	}
+
+	for _, route := range routes {
+		route.Path = strings.TrimSuffix(admin.prefix, "/") + route.Path
+	}
+
+	return routes
+}
+
+// handle authorizes the request and writes the result of the handler as json
+func (admin *Admin) handle(handler func(r *http.Request) (interface{}, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if admin.auth != nil {
+			if err := admin.auth(r); err != nil {
+				writeAdminError(w, http.StatusUnauthorized, err)
+				return
+			}
+		}
+
+		result, err := handler(r)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if badRequest, ok := err.(adminBadRequest); ok {
+				status, err = http.StatusBadRequest, badRequest.error
+			}
+
+			writeAdminError(w, status, err)
+			return
+		}
+
+		if result == nil {
+			result = map[string]string{"status": "ok"}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			admin.logger.Errorf("admin, error writing response: %s", err)
+		}
+	})
+}
+
+// AdminStatus ...
+type AdminStatus struct {
+	Started    bool                   `json:"started"`
+	LogLevel   string                 `json:"log_level"`
+	Components map[ComponentState]int `json:"components"`
+}
+
+func (admin *Admin) status(r *http.Request) (interface{}, error) {
+	status := &AdminStatus{
+		Started:    admin.manager.Started(),
+		LogLevel:   logLevelName(admin.manager.LogLevel()),
+		Components: make(map[ComponentState]int),
+	}
+
+	for _, info := range admin.manager.Components() {
+		status.Components[info.State]++
+	}
+
+	return status, nil
+}
+
+func (admin *Admin) components(r *http.Request) (interface{}, error) {
+	var kinds []Kind
+	if kind := r.URL.Query().Get("kind"); kind != "" {
+		kinds = append(kinds, Kind(kind))
+	}
+
+	return admin.manager.Components(kinds...), nil
+}
+
+// control executes the action on the component with the kind and key of the query
+func (admin *Admin) control(action func(kind Kind, key string) error) func(r *http.Request) (interface{}, error) {
+	return func(r *http.Request) (interface{}, error) {
+		kind, key := Kind(r.URL.Query().Get("kind")), r.URL.Query().Get("key")
+		if kind == "" || key == "" {
+			return nil, adminBadRequest{fmt.Errorf("the kind and the key of the component are required")}
+		}
+
+		if err := action(kind, key); err != nil {
+			return nil, err
+		}
+
+		return &ComponentInfo{Kind: kind, Key: key, State: admin.manager.State(kind, key)}, nil
+	}
+}
+
+// dump returns the content of the work lists, or of the one with the key of the query
+func (admin *Admin) dump(r *http.Request) (interface{}, error) {
+	key := r.URL.Query().Get("key")
+
+	dumps := make(map[string]json.RawMessage)
+	for id, component := range admin.manager.registry.Items(KindWorkList) {
+		dumpable, ok := component.(IDumpable)
+		if !ok || (key != "" && id.Key != key) {
+			continue
+		}
+
+		dump := dumpable.Dump()
+		if !json.Valid([]byte(dump)) {
+			encoded, _ := json.Marshal(dump)
+			dump = string(encoded)
+		}
+		dumps[id.Key] = json.RawMessage(dump)
+	}
+
+	if key != "" && len(dumps) == 0 {
+		return nil, adminBadRequest{fmt.Errorf("the work list %s doesn't exist", key)}
+	}
+
+	return dumps, nil
+}
+
+// drain waits for the work list with the key of the query to process its work, as on a graceful stop,
+// until the timeout of the query
+func (admin *Admin) drain(r *http.Request) (interface{}, error) {
+	key := r.URL.Query().Get("key")
+
+	component, exists := admin.manager.registry.Get(NewComponentId(KindWorkList, key))
+	if !exists {
+		return nil, adminBadRequest{fmt.Errorf("the work list %s doesn't exist", key)}
+	}
+
+	drainable, ok := component.(IDrainable)
+	if !ok {
+		return nil, adminBadRequest{fmt.Errorf("the work list %s can't be drained", key)}
+	}
+
+	var timeout time.Duration
+	if value := r.URL.Query().Get("timeout"); value != "" {
+		var err error
+		if timeout, err = time.ParseDuration(value); err != nil {
+			return nil, adminBadRequest{fmt.Errorf("invalid timeout %s", value)}
+		}
+	}
+
+	ctx, cancel := withTimeout(r.Context(), timeout)
+	defer cancel()
+
+	return nil, drainable.Drain(ctx)
+}
+
+// config returns the value of the key of the query, from the config with the given name or from the first one with it
+func (admin *Admin) config(r *http.Request) (interface{}, error) {
+	key, name := r.URL.Query().Get("key"), r.URL.Query().Get("config")
+	if key == "" {
+		return nil, adminBadRequest{fmt.Errorf("the key is required")}
+	}
+
+	for _, id := range admin.manager.registry.Ids(KindConfig) {
+		if name != "" && id.Key != name {
+			continue
+		}
+
+		config, ok := GetComponent[IConfig](admin.manager, KindConfig, id.Key)
+		if !ok {
+			continue
+		}
+
+		if value := config.Get(key); value != nil {
+			return map[string]interface{}{"config": id.Key, "key": key, "value": value}, nil
+		}
+	}
+
+	return nil, adminBadRequest{fmt.Errorf("the key %s doesn't exist", key)}
+}
+
+func (admin *Admin) reload(r *http.Request) (interface{}, error) {
+	return nil, admin.manager.ReloadConfigs()
+}
+
+func (admin *Admin) logLevel(r *http.Request) (interface{}, error) {
+	return map[string]string{"level": logLevelName(admin.manager.LogLevel())}, nil
+}
+
+func (admin *Admin) setLogLevel(r *http.Request) (interface{}, error) {
+	name := r.URL.Query().Get("level")
+
+	level, exists := logLevels[strings.ToLower(name)]
+	if !exists {
+		return nil, adminBadRequest{fmt.Errorf("invalid log level %s", name)}
+	}
+
+	admin.manager.SetLogLevel(level)
+
+	return map[string]string{"level": logLevelName(level)}, nil
+}
+
+// adminBadRequest is an error of the request, instead of the manager
+type adminBadRequest struct {
+	error
+}
+
+func writeAdminError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+func logLevelName(level logger.Level) string {
+	for name, l := range logLevels {
+		if l == level {
+			return name
+		}
+	}
+
+	return fmt.Sprint(int(level))
+}
+
+// TokenAuth authorizes the requests with the token on the Authorization header, as "Bearer <token>",
+// failing for an empty token, that would authorize the requests without the header
+func TokenAuth(token string) (AdminAuth, error) {
+	if token == "" {
+		return nil, fmt.Errorf("admin, the token can't be empty")
+	}
+
+	return func(r *http.Request) error {
+		const scheme = "Bearer "
+
+		header := r.Header.Get("Authorization")
+		if len(header) <= len(scheme) || !strings.EqualFold(header[:len(scheme)], scheme) {
+			return fmt.Errorf("missing bearer token")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(header[len(scheme):]), []byte(token)) != 1 {
+			return fmt.Errorf("invalid token")
+		}
+
+		return nil
+	}, nil
+}
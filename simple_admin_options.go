@@ -0,0 +1,32 @@
+package manager
+
+// AdminOption ...
+type AdminOption func(admin *Admin)
+
+// Reconfigure ...
+func (admin *Admin) Reconfigure(options ...AdminOption) {
+	for _, option := range options {
+		option(admin)
+	}
+}
+
+// WithAdminAddress sets the address to serve the admin api on its own, when it's added as a process
+func WithAdminAddress(address string) AdminOption {
+	return func(admin *Admin) {
+		admin.address = address
+	}
+}
+
+// WithAdminPrefix sets the prefix of the routes, /admin by default
+func WithAdminPrefix(prefix string) AdminOption {
+	return func(admin *Admin) {
+		admin.prefix = prefix
+	}
+}
+
+// WithAdminAuth sets the authorization of the requests, as TokenAuth
+func WithAdminAuth(auth AdminAuth) AdminOption {
+	return func(admin *Admin) {
+		admin.auth = auth
+	}
+}
@@ -0,0 +1,180 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joaosoft/logger"
+)
+
+func TestAdmin(t *testing.T) {
+	manager := newTestManager()
+
+	worklist := manager.NewSimpleWorkList(NewWorkListConfig("queue", 1, 1, time.Millisecond, FIFO),
+		func(id string, data interface{}) error { return nil }, nil, nil)
+	if err := manager.AddWorkList("queue", worklist); err != nil {
+		t.Fatal(err)
+	}
+
+	process := manager.NewSupervisedProcess("process", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	if err := manager.AddProcess("process", process); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Stop()
+
+	if _, err := TokenAuth(""); err == nil {
+		t.Fatal("expected an error for an empty token")
+	}
+
+	auth, err := TokenAuth("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(manager.NewAdmin(WithAdminAuth(auth)).Handler())
+	defer server.Close()
+
+	request := func(method, path string, authorization string, result interface{}) int {
+		req, err := http.NewRequest(method, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if result != nil {
+			if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return resp.StatusCode
+	}
+
+	for _, authorization := range []string{"", "Bearer invalid", "secret", "Basic secret"} {
+		if status := request(http.MethodGet, "/admin/components", authorization, nil); status != http.StatusUnauthorized {
+			t.Fatalf("expected an unauthorized request with %q, got %d", authorization, status)
+		}
+	}
+
+	var components []*ComponentInfo
+	if status := request(http.MethodGet, "/admin/components", "Bearer secret", &components); status != http.StatusOK || len(components) != 2 {
+		t.Fatalf("expected the components, got %d %v", status, components)
+	}
+
+	var info ComponentInfo
+	if status := request(http.MethodPost, "/admin/components/stop?kind=process&key=process", "Bearer secret", &info); status != http.StatusOK || info.State != StateStopped {
+		t.Fatalf("expected the process to be stopped, got %d %s", status, info.State)
+	}
+	if process.Started() {
+		t.Fatal("expected the process to be stopped")
+	}
+
+	if status := request(http.MethodPost, "/admin/components/start?kind=process&key=process", "Bearer secret", &info); status != http.StatusOK || info.State != StateRunning {
+		t.Fatalf("expected the process to be running, got %d %s", status, info.State)
+	}
+
+	if status := request(http.MethodPost, "/admin/components/pause?kind=process&key=process", "Bearer secret", nil); status != http.StatusInternalServerError {
+		t.Fatalf("expected the process not to be pausable, got %d", status)
+	}
+
+	var dumps map[string]json.RawMessage
+	if status := request(http.MethodGet, "/admin/worklists/dump?key=queue", "Bearer secret", &dumps); status != http.StatusOK || dumps["queue"] == nil {
+		t.Fatalf("expected the dump of the queue, got %d %v", status, dumps)
+	}
+
+	var level map[string]string
+	if status := request(http.MethodPut, "/admin/log/level?level=debug", "Bearer secret", &level); status != http.StatusOK || manager.LogLevel() != logger.DebugLevel {
+		t.Fatalf("expected the debug log level, got %d %v", status, level)
+	}
+
+	if status := request(http.MethodDelete, "/admin/components", "Bearer secret", nil); status != http.StatusMethodNotAllowed {
+		t.Fatalf("expected a method not allowed, got %d", status)
+	}
+}
+
+func TestAdminRoutes(t *testing.T) {
+	webs := map[string]func(manager *Manager, host string) IWeb{
+		"http":   (*Manager).NewSimpleWebHttp,
+		"echo":   (*Manager).NewSimpleWebEcho,
+		"server": (*Manager).NewSimpleWebServer,
+	}
+
+	for name, newWeb := range webs {
+		t.Run(name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			host := listener.Addr().String()
+			listener.Close()
+
+			manager := newTestManager()
+			if err := manager.AddWeb("api", newWeb(manager, host)); err != nil {
+				t.Fatal(err)
+			}
+			if err := manager.AddAdminRoutes("api", manager.NewAdmin()); err != nil {
+				t.Fatal(err)
+			}
+
+			// the web of the web package routes by method on its own, and isn't served on the tests
+			if name == "server" {
+				return
+			}
+
+			if err := manager.Start(); err != nil {
+				t.Fatal(err)
+			}
+			defer manager.Stop()
+
+			request := func(method, path string) int {
+				req, err := http.NewRequest(method, "http://"+host+path, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				resp.Body.Close()
+
+				return resp.StatusCode
+			}
+
+			// the routes that share a path are served by their method
+			if status := request(http.MethodPut, "/admin/log/level?level=debug"); status != http.StatusOK || manager.LogLevel() != logger.DebugLevel {
+				t.Fatalf("expected the debug log level, got %d", status)
+			}
+			if status := request(http.MethodGet, "/admin/log/level"); status != http.StatusOK {
+				t.Fatalf("expected the log level, got %d", status)
+			}
+
+			if status := request(http.MethodGet, "/admin/components/stop?kind=web&key=api"); status != http.StatusMethodNotAllowed {
+				t.Fatalf("expected the control route to refuse a get, got %d", status)
+			}
+			if !manager.GetWeb("api").Started() {
+				t.Fatal("expected the web to be kept started")
+			}
+		})
+	}
+}
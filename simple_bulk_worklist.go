@@ -1,7 +1,7 @@
 package manager
 
 import (
-	"sync"
+	"context"
 
 	"github.com/joaosoft/logger"
 )
@@ -13,39 +13,37 @@ type SimpleBulkWorkList struct {
 	handler                             BulkWorkHandler
 	bulkWorkRecoverWastedRetriesHandler BulkWorkRecoverWastedRetriesHandler
 	bulkWorkRecoverHandler              BulkWorkRecoverHandler
+	bulkWorkDrainHandler                BulkWorkDrainHandler
 	list                                IList
 	workers                             []*BulkWorker
+	metrics                             *workMetrics
 	logger                              logger.ILogger
-	started                             bool
+	state                               lifecycleState
 }
 
 // NewSimpleBulkWorkList ...
-func (manager *Manager) NewSimpleBulkWorkList(config *BulkWorkListConfig, handler BulkWorkHandler, bulkWorkRecoverHandler BulkWorkRecoverHandler, bulkWorkRecoverWastedRetriesHandler BulkWorkRecoverWastedRetriesHandler) IWorkList {
-	return &SimpleBulkWorkList{
+func (manager *Manager) NewSimpleBulkWorkList(config *BulkWorkListConfig, handler BulkWorkHandler, bulkWorkRecoverHandler BulkWorkRecoverHandler, bulkWorkRecoverWastedRetriesHandler BulkWorkRecoverWastedRetriesHandler, options ...SimpleBulkWorkListOption) IWorkList {
+	bulkWorklist := &SimpleBulkWorkList{
 		name:                                config.Name,
-		list:                                manager.NewQueue(WithMode(config.Mode)),
+		list:                                manager.NewQueue(WithMode(config.Mode), WithName(config.Name)),
 		config:                              config,
 		handler:                             handler,
 		bulkWorkRecoverHandler:              bulkWorkRecoverHandler,
 		bulkWorkRecoverWastedRetriesHandler: bulkWorkRecoverWastedRetriesHandler,
+		metrics:                             newWorkMetrics(manager.metrics, config.Name),
 		logger:                              manager.logger,
 	}
+	bulkWorklist.Reconfigure(options...)
+
+	return bulkWorklist
 }
 
 // Start ...
-func (bulkWorklist *SimpleBulkWorkList) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
+func (bulkWorklist *SimpleBulkWorkList) Start(ctx context.Context) error {
+	bulkWorklist.state.lock()
+	defer bulkWorklist.state.unlock()
 
-	defer wg.Done()
-
-	if bulkWorklist.started {
+	if bulkWorklist.state.isStarted() {
 		return nil
 	}
 
@@ -53,30 +51,36 @@ func (bulkWorklist *SimpleBulkWorkList) Start(waitGroup ...*sync.WaitGroup) erro
 	for i := 1; i <= bulkWorklist.config.MaxWorkers; i++ {
 		bulkWorklist.logger.Infof("starting worker [ %d ]", i)
 		worker := NewBulkWorker(i, bulkWorklist.config, bulkWorklist.handler, bulkWorklist.list, bulkWorklist.bulkWorkRecoverHandler, bulkWorklist.bulkWorkRecoverWastedRetriesHandler, bulkWorklist.logger)
+		worker.metrics = bulkWorklist.metrics
 		worker.Start()
 		workers = append(workers, worker)
 	}
 	bulkWorklist.workers = workers
 
-	bulkWorklist.started = true
+	bulkWorklist.state.setStarted(true)
 
 	return nil
 }
 
-// Stop ...
-func (bulkWorklist *SimpleBulkWorkList) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
+// Drain waits for the workers to process the work in the list, giving the work left to the drain handler
+// when the context is done
+func (bulkWorklist *SimpleBulkWorkList) Drain(ctx context.Context) error {
+	bulkWorklist.state.lock()
+	defer bulkWorklist.state.unlock()
+
+	if !bulkWorklist.state.isStarted() {
+		return nil
 	}
 
-	defer wg.Done()
+	return drainList(ctx, bulkWorklist.name, bulkWorklist.list, bulkWorklist.bulkWorkDrainHandler, bulkWorklist.logger)
+}
 
-	if !bulkWorklist.started {
+// Stop ...
+func (bulkWorklist *SimpleBulkWorkList) Stop(ctx context.Context) error {
+	bulkWorklist.state.lock()
+	defer bulkWorklist.state.unlock()
+
+	if !bulkWorklist.state.isStarted() {
 		return nil
 	}
 
@@ -85,14 +89,24 @@ func (bulkWorklist *SimpleBulkWorkList) Stop(waitGroup ...*sync.WaitGroup) error
 		worker.Stop()
 	}
 
-	bulkWorklist.started = false
+	bulkWorklist.state.setStarted(false)
 
 	return nil
 }
 
+// Healthy ...
+func (bulkWorklist *SimpleBulkWorkList) Healthy(ctx context.Context) error {
+	return checkListSize(bulkWorklist.list, bulkWorklist.config.HealthThreshold)
+}
+
 // Started ...
 func (bulkWorklist *SimpleBulkWorkList) Started() bool {
-	return bulkWorklist.started
+	return bulkWorklist.state.isStarted()
+}
+
+// Dump ...
+func (bulkWorklist *SimpleBulkWorkList) Dump() string {
+	return bulkWorklist.list.Dump()
 }
 
 // AddWork ...
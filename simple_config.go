@@ -1,144 +1,340 @@
 package manager
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
 	"time"
 
 	"github.com/joaosoft/logger"
-
-	"bytes"
-
 	"github.com/spf13/viper"
 )
 
 // SimpleConfig ...
 type SimpleConfig struct {
-	file   string
-	obj    interface{}
-	bytes  []byte
-	viper  *viper.Viper
-	logger logger.ILogger
+	configAccessors
+	file            string
+	schemaFile      string
+	secretProviders map[string]SecretProvider
+	secrets         []*configSecret
+	obj             interface{}
+	bytes           []byte
+	viper           *viper.Viper
+	changeHandlers  []ConfigChangeHandler
+	keyHandlers     map[string][]ConfigKeyChangeHandler
+	debounce        time.Duration
+	pollInterval    time.Duration
+	watcher         *configWatcher
+	logger          logger.ILogger
+	mux             sync.RWMutex
+	reloadMux       sync.Mutex
 }
 
-func NewSimpleConfig(file string, obj interface{}) (IConfig, error) {
-	if bytes, err := ReadFile(file, obj); err != nil {
-		return nil, err
-	} else {
-		return &SimpleConfig{
-			file:   file,
-			obj:    obj,
-			bytes:  bytes,
-			viper:  loadViper(bytes),
-			logger: logger.Instance,
-		}, err
-	}
+func NewSimpleConfig(file string, obj interface{}, options ...SimpleConfigOption) (ITypedConfig, error) {
+	return newSimpleConfig(file, obj, logger.Instance, options...)
 }
 
 // NewSimpleConfig...
-func (manager *Manager) NewSimpleConfig(file string, obj interface{}) (IConfig, error) {
-	if bytes, err := ReadFile(file, obj); err != nil {
+func (manager *Manager) NewSimpleConfig(file string, obj interface{}, options ...SimpleConfigOption) (ITypedConfig, error) {
+	return newSimpleConfig(file, obj, manager.logger, options...)
+}
+
+func newSimpleConfig(file string, obj interface{}, logger logger.ILogger, options ...SimpleConfigOption) (*SimpleConfig, error) {
+	config := &SimpleConfig{
+		file:            file,
+		obj:             obj,
+		keyHandlers:     make(map[string][]ConfigKeyChangeHandler),
+		secretProviders: defaultSecretProviders(),
+		debounce:        defaultConfigDebounce,
+		logger:          logger,
+	}
+	config.configAccessors = configAccessors{viper: config.getViper}
+	config.Reconfigure(options...)
+
+	bytes, secrets, err := config.read(obj)
+	if err != nil {
 		return nil, err
-	} else {
-		return &SimpleConfig{
-			file:   file,
-			obj:    obj,
-			bytes:  bytes,
-			viper:  loadViper(bytes),
-			logger: manager.logger,
-		}, err
 	}
+
+	if err = config.validate(obj, bytes); err != nil {
+		return nil, err
+	}
+
+	config.bytes, config.secrets, config.viper = bytes, secrets, loadViper(bytes)
+
+	return config, nil
 }
 
 // Get ...
 func (simple *SimpleConfig) Get(key string) interface{} {
-	return simple.viper.Get(key)
+	return simple.getViper().Get(key)
 }
 
 // GetString ...
 func (simple *SimpleConfig) GetString(key string) string {
-	return simple.viper.GetString(key)
+	return simple.getViper().GetString(key)
 }
 
 // GetBool ...
 func (simple *SimpleConfig) GetBool(key string) bool {
-	return simple.viper.GetBool(key)
+	return simple.getViper().GetBool(key)
 }
 
 // GetInt ...
 func (simple *SimpleConfig) GetInt(key string) int {
-	return simple.viper.GetInt(key)
+	return simple.getViper().GetInt(key)
 }
 
 // GetInt64 ...
 func (simple *SimpleConfig) GetInt64(key string) int64 {
-	return simple.viper.GetInt64(key)
+	return simple.getViper().GetInt64(key)
 }
 
 // GetFloat64 ...
 func (simple *SimpleConfig) GetFloat64(key string) float64 {
-	return simple.viper.GetFloat64(key)
+	return simple.getViper().GetFloat64(key)
 }
 
 // GetTime ...
 func (simple *SimpleConfig) GetTime(key string) time.Time {
-	return simple.viper.GetTime(key)
+	return simple.getViper().GetTime(key)
 }
 
 // GetDuration ...
 func (simple *SimpleConfig) GetDuration(key string) time.Duration {
-	return simple.viper.GetDuration(key)
+	return simple.getViper().GetDuration(key)
 }
 
 // GetStringSlice ...
 func (simple *SimpleConfig) GetStringSlice(key string) []string {
-	return simple.viper.GetStringSlice(key)
+	return simple.getViper().GetStringSlice(key)
 }
 
 // GetStringMap ...
 func (simple *SimpleConfig) GetStringMap(key string) map[string]interface{} {
-	return simple.viper.GetStringMap(key)
+	return simple.getViper().GetStringMap(key)
 }
 
 // GetStringMapString ...
 func (simple *SimpleConfig) GetStringMapString(key string) map[string]string {
-	return simple.viper.GetStringMapString(key)
+	return simple.getViper().GetStringMapString(key)
 }
 
 // GetStringMapStringSlice ...
 func (simple *SimpleConfig) GetStringMapStringSlice(key string) map[string][]string {
-	return simple.viper.GetStringMapStringSlice(key)
+	return simple.getViper().GetStringMapStringSlice(key)
 }
 
-// GetObj ...
+// GetObj returns the bound object, that is replaced by a new one on each reload
 func (simple *SimpleConfig) GetObj() interface{} {
+	simple.mux.RLock()
+	defer simple.mux.RUnlock()
+
 	return simple.obj
 }
 
 // Set ...
 func (simple *SimpleConfig) Set(config interface{}) {
-	simple.obj = simple
+	simple.mux.Lock()
+	defer simple.mux.Unlock()
+
+	simple.obj = config
+}
+
+// OnChange registers a handler called with the previous and the new object after each reload
+func (simple *SimpleConfig) OnChange(handler ConfigChangeHandler) {
+	simple.mux.Lock()
+	defer simple.mux.Unlock()
+
+	simple.changeHandlers = append(simple.changeHandlers, handler)
+}
+
+// OnKeyChange registers a handler called when the value of the key changes on a reload
+func (simple *SimpleConfig) OnKeyChange(key string, handler ConfigKeyChangeHandler) {
+	simple.mux.Lock()
+	defer simple.mux.Unlock()
+
+	simple.keyHandlers[key] = append(simple.keyHandlers[key], handler)
 }
 
-// Update ...
+// Reload reads the file into a new object, that replaces the bound one at once, and calls the change handlers.
+// the previous object is kept when the file can't be read or when it is invalid
 func (simple *SimpleConfig) Reload() error {
-	if bytes, err := ReadFile(simple.file, simple.obj); err != nil {
+	simple.reloadMux.Lock()
+	defer simple.reloadMux.Unlock()
+
+	obj := newConfigObj(simple.GetObj())
+	bytes, secrets, err := simple.read(obj)
+	if err != nil {
+		return err
+	}
+
+	if err = simple.validate(obj, bytes); err != nil {
+		simple.logger.Errorf("refusing the invalid reload of the config [ file: %s ]", simple.file)
 		return err
-	} else {
-		simple.viper = loadViper(simple.bytes)
-		simple.bytes = bytes
 	}
+	viper := loadViper(bytes)
+
+	simple.mux.Lock()
+	oldObj, oldViper := simple.obj, simple.viper
+	simple.obj, simple.bytes, simple.secrets, simple.viper = obj, bytes, secrets, viper
+	changeHandlers, keyHandlers := copyConfigHandlers(simple.changeHandlers, simple.keyHandlers)
+	simple.mux.Unlock()
+
+	notifyConfigChange(simple.file, oldObj, obj, oldViper, viper, changeHandlers, keyHandlers, simple.logger)
 
 	return nil
 }
 
-// Save ...
+// Save writes the object to the file when it is valid, with the format of the file, and reloads it.
+// the values with the resolved secrets are written with their references, so the secrets never reach the file
 func (simple *SimpleConfig) Save() error {
-	if err := WriteFile(simple.file, simple.obj); err != nil {
+	obj := simple.GetObj()
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	// the json of the object is validated, as it is what is written
+	if err = validateConfig(simple.file, simple.schemaFile, obj, data); err != nil {
+		return err
+	}
+
+	simple.mux.RLock()
+	secrets := simple.secrets
+	simple.mux.RUnlock()
+
+	values, err := restoreSecrets(obj, secrets)
+	if err != nil {
+		return err
+	}
+
+	if err := writeConfigFile(simple.file, values); err != nil {
 		return err
 	}
 
 	return simple.Reload()
 }
 
+// Watch reloads the config when its file changes, waiting for the changes to settle for the debounce time.
+// it uses the file system notifications, falling back to polling when they aren't available
+func (simple *SimpleConfig) Watch() error {
+	simple.mux.Lock()
+	defer simple.mux.Unlock()
+
+	if simple.watcher != nil {
+		return nil
+	}
+
+	watcher, err := newConfigWatcher(simple.file, simple.debounce, simple.pollInterval, simple.logger, func() {
+		if err := simple.Reload(); err != nil {
+			simple.logger.Errorf("error reloading the config [ file: %s ]: %s", simple.file, err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	simple.watcher = watcher
+
+	return nil
+}
+
+// Unwatch stops watching the file
+func (simple *SimpleConfig) Unwatch() {
+	simple.mux.Lock()
+	watcher := simple.watcher
+	simple.watcher = nil
+	simple.mux.Unlock()
+
+	if watcher != nil {
+		watcher.stop()
+	}
+}
+
+// read reads the file into the object, resolving the references to secrets of its values,
+// and returns the json of the file, whatever its format
+func (simple *SimpleConfig) read(obj interface{}) ([]byte, []*configSecret, error) {
+	data, err := ReadFile(simple.file, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values, err := decodeConfigFile(simple.file, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	secrets, err := resolveSecrets(simple.file, values, simple.secretProviders)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if data, err = json.Marshal(values); err != nil {
+		return nil, nil, err
+	}
+
+	if obj != nil {
+		if err = json.Unmarshal(data, obj); err != nil {
+			return nil, nil, fmt.Errorf("invalid config file %s: %s", simple.file, err)
+		}
+	}
+
+	return data, secrets, nil
+}
+
+// validate checks the object and the json of the file
+func (simple *SimpleConfig) validate(obj interface{}, data []byte) error {
+	return validateConfig(simple.file, simple.schemaFile, obj, data)
+}
+
+func (simple *SimpleConfig) getViper() *viper.Viper {
+	simple.mux.RLock()
+	defer simple.mux.RUnlock()
+
+	return simple.viper
+}
+
+// copyConfigHandlers copies the handlers, to be called without holding the lock of the config
+func copyConfigHandlers(changeHandlers []ConfigChangeHandler, keyHandlers map[string][]ConfigKeyChangeHandler) ([]ConfigChangeHandler, map[string][]ConfigKeyChangeHandler) {
+	keyHandlersCopy := make(map[string][]ConfigKeyChangeHandler, len(keyHandlers))
+	for key, handlers := range keyHandlers {
+		keyHandlersCopy[key] = append([]ConfigKeyChangeHandler(nil), handlers...)
+	}
+
+	return append([]ConfigChangeHandler(nil), changeHandlers...), keyHandlersCopy
+}
+
+// notifyConfigChange calls the change handlers, and the key handlers of the keys with different values
+func notifyConfigChange(name string, oldObj, newObj interface{}, oldViper, newViper *viper.Viper, changeHandlers []ConfigChangeHandler, keyHandlers map[string][]ConfigKeyChangeHandler, logger logger.ILogger) {
+	for _, handler := range changeHandlers {
+		handler(oldObj, newObj)
+	}
+
+	for key, handlers := range keyHandlers {
+		oldValue, newValue := oldViper.Get(key), newViper.Get(key)
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		logger.Infof("config key changed [ config: %s, key: %s ]", name, key)
+		for _, handler := range handlers {
+			handler(key, oldValue, newValue)
+		}
+	}
+}
+
+// newConfigObj creates an empty object of the type of the bound one, or nil when it isn't a pointer
+func newConfigObj(obj interface{}) interface{} {
+	value := reflect.ValueOf(obj)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return nil
+	}
+
+	return reflect.New(value.Elem().Type()).Interface()
+}
+
 func loadViper(b []byte) *viper.Viper {
 	viper := viper.New()
 	viper.SetConfigType("json")
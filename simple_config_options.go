@@ -0,0 +1,45 @@
+package manager
+
+import (
+	"time"
+)
+
+// SimpleConfigOption ...
+type SimpleConfigOption func(config *SimpleConfig)
+
+// Reconfigure ...
+func (simple *SimpleConfig) Reconfigure(options ...SimpleConfigOption) {
+	for _, option := range options {
+		option(simple)
+	}
+}
+
+// WithConfigDebounce sets the time the watcher waits for the changes of the file to settle before reloading it
+func WithConfigDebounce(debounce time.Duration) SimpleConfigOption {
+	return func(config *SimpleConfig) {
+		config.debounce = debounce
+	}
+}
+
+// WithConfigPolling makes the watcher check the file on each interval instead of using the file system notifications,
+// as on the network file systems where they aren't delivered
+func WithConfigPolling(interval time.Duration) SimpleConfigOption {
+	return func(config *SimpleConfig) {
+		config.pollInterval = interval
+	}
+}
+
+// WithConfigSchema validates the config file with the json schema of the file, besides the validate tags of the object
+func WithConfigSchema(schemaFile string) SimpleConfigOption {
+	return func(config *SimpleConfig) {
+		config.schemaFile = schemaFile
+	}
+}
+
+// WithSecretProvider resolves the references to secrets of the scheme with the provider, as ${vault:db_password},
+// besides the ${env:NAME} and ${file:/path} ones
+func WithSecretProvider(scheme string, provider SecretProvider) SimpleConfigOption {
+	return func(config *SimpleConfig) {
+		config.secretProviders[scheme] = provider
+	}
+}
@@ -0,0 +1,143 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/joaosoft/logger"
+)
+
+const (
+	// defaultConfigDebounce is the time to wait for the changes of a config file to settle before reloading it
+	defaultConfigDebounce = 100 * time.Millisecond
+	// defaultConfigPollInterval is the interval to check a config file when the file system notifications aren't available
+	defaultConfigPollInterval = time.Second
+)
+
+// configWatcher calls the reload when the file changes, through the file system notifications or by polling it
+type configWatcher struct {
+	file     string
+	debounce time.Duration
+	reload   func()
+	info     os.FileInfo
+	logger   logger.ILogger
+	quit     chan struct{}
+	done     chan struct{}
+}
+
+func newConfigWatcher(file string, debounce, pollInterval time.Duration, logger logger.ILogger, reload func()) (*configWatcher, error) {
+	if !Exists(file) {
+		file = global[path_key].(string) + file
+	}
+
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher := &configWatcher{
+		file:     file,
+		debounce: debounce,
+		reload:   reload,
+		info:     info,
+		logger:   logger,
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	if pollInterval <= 0 {
+		// the directory is watched, so the files replaced by a rename or by a symlink swap are noticed
+		notifier, err := fsnotify.NewWatcher()
+		if err == nil {
+			if err = notifier.Add(filepath.Dir(file)); err != nil {
+				notifier.Close()
+			}
+		}
+
+		if err == nil {
+			logger.Infof("watching the config [ file: %s ]", file)
+			go watcher.run(notifier, nil)
+			return watcher, nil
+		}
+
+		logger.Warnf("file system notifications aren't available, polling the config [ file: %s ]: %s", file, err)
+		pollInterval = defaultConfigPollInterval
+	}
+
+	logger.Infof("polling the config [ file: %s, interval: %s ]", file, pollInterval)
+	go watcher.run(nil, time.NewTicker(pollInterval))
+
+	return watcher, nil
+}
+
+func (watcher *configWatcher) run(notifier *fsnotify.Watcher, ticker *time.Ticker) {
+	defer close(watcher.done)
+
+	var events <-chan fsnotify.Event
+	var errors <-chan error
+	if notifier != nil {
+		defer notifier.Close()
+		events, errors = notifier.Events, notifier.Errors
+	}
+
+	var ticks <-chan time.Time
+	if ticker != nil {
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-watcher.quit:
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			if filepath.Clean(event.Name) == watcher.file || watcher.changed() {
+				debounce = time.After(watcher.debounce)
+			}
+		case err, ok := <-errors:
+			if ok {
+				watcher.logger.Errorf("error watching the config [ file: %s ]: %s", watcher.file, err)
+			}
+		case <-ticks:
+			if watcher.changed() {
+				debounce = time.After(watcher.debounce)
+			}
+		case <-debounce:
+			debounce = nil
+			watcher.changed()
+			watcher.reload()
+		}
+	}
+}
+
+// changed checks if the file is another one or if it was modified since the last check
+func (watcher *configWatcher) changed() bool {
+	info, err := os.Stat(watcher.file)
+	if err != nil {
+		return false
+	}
+
+	previous := watcher.info
+	watcher.info = info
+
+	return !os.SameFile(previous, info) || !previous.ModTime().Equal(info.ModTime()) || previous.Size() != info.Size()
+}
+
+func (watcher *configWatcher) stop() {
+	close(watcher.quit)
+	<-watcher.done
+
+	watcher.logger.Infof("stopped watching the config [ file: %s ]", watcher.file)
+}
@@ -0,0 +1,90 @@
+package manager
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSimpleConfigWatch(t *testing.T) {
+	type appConfig struct {
+		Name       string `json:"name"`
+		MaxWorkers int    `json:"max_workers"`
+	}
+
+	for _, test := range []struct {
+		name    string
+		options []SimpleConfigOption
+	}{
+		{name: "notifications", options: []SimpleConfigOption{WithConfigDebounce(10 * time.Millisecond)}},
+		{name: "polling", options: []SimpleConfigOption{WithConfigDebounce(10 * time.Millisecond), WithConfigPolling(10 * time.Millisecond)}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "app.json")
+			if err := ioutil.WriteFile(file, []byte(`{"name": "first", "max_workers": 1}`), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			manager := newTestManager()
+			config, err := manager.NewSimpleConfig(file, &appConfig{}, test.options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			watchable := config.(IWatchableConfig)
+
+			worklist := manager.NewSimpleWorkList(NewWorkListConfig("queue", 1, 1, time.Millisecond, FIFO),
+				func(id string, data interface{}) error { return nil }, nil, nil).(*SimpleWorkList)
+			if err := worklist.Start(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			defer worklist.Stop(context.Background())
+
+			changes := make(chan [2]*appConfig, 1)
+			watchable.OnChange(func(oldObj, newObj interface{}) {
+				changes <- [2]*appConfig{oldObj.(*appConfig), newObj.(*appConfig)}
+			})
+
+			nameChanges := make(chan struct{}, 1)
+			watchable.OnKeyChange("name", func(key string, oldValue, newValue interface{}) { nameChanges <- struct{}{} })
+			resized := make(chan struct{}, 1)
+			watchable.OnKeyChange("max_workers", func(key string, oldValue, newValue interface{}) {
+				if err := worklist.SetMaxWorkers(config.GetInt(key)); err != nil {
+					t.Error(err)
+				}
+				resized <- struct{}{}
+			})
+
+			if err := watchable.Watch(); err != nil {
+				t.Fatal(err)
+			}
+			defer watchable.Unwatch()
+
+			if err := ioutil.WriteFile(file, []byte(`{"name": "first", "max_workers": 3}`), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case change := <-changes:
+				if change[0].MaxWorkers != 1 || change[1].MaxWorkers != 3 {
+					t.Fatalf("unexpected change from %+v to %+v", change[0], change[1])
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("expected the config to be reloaded")
+			}
+
+			if config.GetObj().(*appConfig).MaxWorkers != 3 || config.GetInt("max_workers") != 3 {
+				t.Fatalf("expected the new values, got %+v and %d", config.GetObj(), config.GetInt("max_workers"))
+			}
+
+			<-resized
+			worklist.state.lock()
+			workers := len(worklist.workers)
+			worklist.state.unlock()
+			if workers != 3 || len(nameChanges) != 0 {
+				t.Fatalf("expected 3 workers and no name changes, got %d workers and %d name changes", workers, len(nameChanges))
+			}
+		})
+	}
+}
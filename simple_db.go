@@ -2,20 +2,29 @@ package manager
 
 import (
 	"database/sql"
+	"fmt"
+	"time"
+
 	"github.com/joaosoft/logger"
 
-	"sync"
+	"context"
 
 	_ "github.com/go-sql-driver/mysql" // mysql driver
 	_ "github.com/lib/pq"              // postgres driver
 )
 
+const (
+	defaultDBPingBackoff    = time.Second
+	defaultDBMaxPingBackoff = 30 * time.Second
+	defaultDBPingTimeout    = 5 * time.Second
+)
+
 // SimpleDB ...
 type SimpleDB struct {
 	*sql.DB
 	logger logger.ILogger
-	config  *DBConfig
-	started bool
+	config *DBConfig
+	state  lifecycleState
 }
 
 // NewSimpleDB ...
@@ -31,59 +40,120 @@ func (db *SimpleDB) Get() *sql.DB {
 	return db.DB
 }
 
-// Start ...
-func (db *SimpleDB) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
+// Start opens the database and pings it, retrying with backoff, failing when it isn't reachable
+func (db *SimpleDB) Start(ctx context.Context) error {
+	db.state.lock()
+	defer db.state.unlock()
 
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
+	if db.state.isStarted() {
+		return nil
 	}
 
-	defer wg.Done()
-
-	if db.started {
-		return nil
+	conn, err := db.config.Connect()
+	if err != nil {
+		return err
 	}
 
-	if conn, err := db.config.Connect(); err != nil {
+	if err = db.ping(ctx, conn); err != nil {
+		conn.Close()
 		return err
-	} else {
-		db.DB = conn
-		db.started = true
 	}
 
+	db.DB = conn
+	db.state.setStarted(true)
+
 	return nil
 }
 
 // Stop ...
-func (db *SimpleDB) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
+func (db *SimpleDB) Stop(ctx context.Context) error {
+	db.state.lock()
+	defer db.state.unlock()
 
-	defer wg.Done()
-
-	if !db.started {
+	if !db.state.isStarted() {
 		return nil
 	}
 
 	if err := db.Close(); err != nil {
 		return err
 	}
-	db.started = false
+	db.state.setStarted(false)
 
 	return nil
 }
 
+// Healthy ...
+func (db *SimpleDB) Healthy(ctx context.Context) error {
+	if !db.state.isStarted() {
+		return fmt.Errorf("database not started")
+	}
+
+	return db.DB.PingContext(ctx)
+}
+
+// Stats returns the statistics of the connection pool, that are empty while the database isn't started
+func (db *SimpleDB) Stats() sql.DBStats {
+	if !db.state.isStarted() {
+		return sql.DBStats{}
+	}
+
+	return db.DB.Stats()
+}
+
+// Ping ...
+func (db *SimpleDB) Ping(ctx context.Context) error {
+	return db.Healthy(ctx)
+}
+
 // Started ...
 func (db *SimpleDB) Started() bool {
-	return db.started
+	return db.state.isStarted()
+}
+
+// ping pings the database until it answers, waiting for the backoff between the retries
+func (db *SimpleDB) ping(ctx context.Context, conn *sql.DB) error {
+	backoff, err := parseConfigDuration(db.config.PingBackoff, defaultDBPingBackoff)
+	if err != nil {
+		return fmt.Errorf("invalid ping_backoff: %s", err)
+	}
+
+	// a backoff that isn't positive would retry the pings without waiting, as doubling it keeps it the same
+	if backoff <= 0 {
+		return fmt.Errorf("invalid ping_backoff: %s isn't positive", backoff)
+	}
+
+	timeout, err := parseConfigDuration(db.config.PingTimeout, defaultDBPingTimeout)
+	if err != nil {
+		return fmt.Errorf("invalid ping_timeout: %s", err)
+	}
+
+	if timeout <= 0 {
+		return fmt.Errorf("invalid ping_timeout: %s isn't positive", timeout)
+	}
+
+	for attempt := 1; ; attempt++ {
+		pingCtx, cancel := context.WithTimeout(ctx, timeout)
+		err = conn.PingContext(pingCtx)
+		cancel()
+
+		if err == nil {
+			return nil
+		}
+
+		if attempt > db.config.PingRetries {
+			return fmt.Errorf("the %s database isn't reachable after %d attempts: %s", db.config.Driver, attempt, err)
+		}
+
+		db.logger.Warnf("error pinging the database, retrying [ driver: %s, attempt: %d, backoff: %s ]: %s", db.config.Driver, attempt, backoff, err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("the %s database isn't reachable: %s", db.config.Driver, ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		if backoff *= 2; backoff > defaultDBMaxPingBackoff {
+			backoff = defaultDBMaxPingBackoff
+		}
+	}
 }
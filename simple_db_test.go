@@ -0,0 +1,126 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testDBDriver is a database driver that refuses the connections to a data source
+// for the number of failures set for it, as "flaky" with 2 failures, or always when it is negative
+type testDBDriver struct {
+	failures map[string]int
+	mux      sync.Mutex
+}
+
+var testDB = &testDBDriver{failures: make(map[string]int)}
+
+func init() {
+	sql.Register("manager-test", testDB)
+}
+
+func (d *testDBDriver) Open(name string) (driver.Conn, error) {
+	if d.refuse(name) {
+		return nil, fmt.Errorf("connection refused")
+	}
+
+	return testDBConn{driver: d, name: name}, nil
+}
+
+func (d *testDBDriver) refuse(name string) bool {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	if d.failures[name] != 0 {
+		d.failures[name]--
+		return true
+	}
+
+	return false
+}
+
+func (d *testDBDriver) fail(name string, failures int) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	d.failures[name] = failures
+}
+
+type testDBConn struct {
+	driver *testDBDriver
+	name   string
+}
+
+func (conn testDBConn) Ping(ctx context.Context) error {
+	if conn.driver.refuse(conn.name) {
+		return fmt.Errorf("connection refused")
+	}
+
+	return nil
+}
+
+func (testDBConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (testDBConn) Close() error {
+	return nil
+}
+
+func (testDBConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func TestSimpleDB(t *testing.T) {
+	manager := newTestManager()
+
+	config := &DBConfig{
+		Driver:          "manager-test",
+		DataSource:      "flaky",
+		MaxOpenConns:    3,
+		MaxIdleConns:    1,
+		ConnMaxLifetime: "1m",
+		PingRetries:     2,
+		PingBackoff:     "1ms",
+	}
+	testDB.fail("flaky", 2)
+
+	db := manager.NewSimpleDB(config)
+	if err := db.Ping(context.Background()); err == nil || db.Stats().MaxOpenConnections != 0 {
+		t.Fatal("expected the database not to be pinged before it is started")
+	}
+
+	if err := db.(*SimpleDB).Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer db.(*SimpleDB).Stop(context.Background())
+
+	if err := db.Ping(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if stats := db.Stats(); stats.MaxOpenConnections != 3 || stats.OpenConnections != 1 {
+		t.Fatalf("expected the pool settings on the stats, got %+v", stats)
+	}
+
+	testDB.fail("unreachable", -1)
+	unreachable := manager.NewSimpleDB(&DBConfig{Driver: "manager-test", DataSource: "unreachable", PingRetries: 1, PingBackoff: "1ms"})
+	if err := unreachable.(*SimpleDB).Start(context.Background()); err == nil || !strings.Contains(err.Error(), "after 2 attempts") || unreachable.Started() {
+		t.Fatalf("expected the unreachable database to fail the start, got %v", err)
+	}
+
+	for _, backoff := range []string{"0s", "-1s"} {
+		spinning := manager.NewSimpleDB(&DBConfig{Driver: "manager-test", DataSource: "unreachable", PingRetries: 1000000, PingBackoff: backoff})
+		if err := spinning.(*SimpleDB).Start(context.Background()); err == nil || !strings.Contains(err.Error(), "invalid ping_backoff") || spinning.Started() {
+			t.Fatalf("expected the ping backoff %s to fail the start, got %v", backoff, err)
+		}
+	}
+
+	if err := ValidateConfig(&DBConfig{Driver: "manager-test", DataSource: "flaky", ConnMaxIdleTime: "forever"}); err == nil || !strings.Contains(err.Error(), "conn_max_idle_time") {
+		t.Fatalf("expected the invalid duration to be reported, got %v", err)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"github.com/joaosoft/web"
 
 	"fmt"
+	"strconv"
+	"time"
 )
 
 // Headers ...
@@ -12,8 +14,9 @@ type Headers map[string][]string
 
 // SimpleGateway ...
 type SimpleGateway struct {
-	client *web.Client
-	logger logger.ILogger
+	client  *web.Client
+	metrics *Metrics
+	logger  logger.ILogger
 }
 
 // NewSimpleGateway ...
@@ -24,15 +27,27 @@ func (manager *Manager) NewSimpleGateway() (IGateway, error) {
 	}
 
 	return &SimpleGateway{
-		client: client,
-		logger: manager.logger,
+		client:  client,
+		metrics: manager.metrics,
+		logger:  manager.logger,
 	}, nil
 }
 
 // Request ...
-func (gateway *SimpleGateway) Request(method, host, endpoint string, contentType string, headers map[string][]string, body []byte) (int, []byte, error) {
+func (gateway *SimpleGateway) Request(method, host, endpoint string, contentType string, headers map[string][]string, body []byte) (status int, response []byte, err error) {
 	url := fmt.Sprintf("%s%s", host, endpoint)
 
+	start := time.Now()
+	defer func() {
+		code := strconv.Itoa(status)
+		if err != nil {
+			code = "error"
+		}
+
+		gateway.metrics.Counter("manager_gateway_requests_total", "Number of gateway requests.", Labels{"method": method, "host": host, "code": code}).Inc()
+		gateway.metrics.Histogram("manager_gateway_request_duration_seconds", "Latency of the gateway requests.", nil, Labels{"method": method, "host": host}).ObserveDuration(start)
+	}()
+
 	request, err := gateway.client.NewRequest(web.Method(method), url, web.ContentType(contentType), headers)
 	if err != nil {
 		panic(err)
@@ -42,10 +57,10 @@ func (gateway *SimpleGateway) Request(method, host, endpoint string, contentType
 		request.WithBody(body)
 	}
 
-	response, err := request.Send()
+	webResponse, err := request.Send()
 	if err != nil {
 		return 0, nil, err
 	}
 
-	return int(response.Status), response.Body, nil
+	return int(webResponse.Status), webResponse.Body, nil
 }
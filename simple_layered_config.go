@@ -0,0 +1,512 @@
+package manager
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joaosoft/logger"
+	"github.com/spf13/viper"
+)
+
+// ConfigLayer is a source of the values of a layered config, from the lowest to the highest precedence
+type ConfigLayer string
+
+const (
+	LayerDefaults ConfigLayer = "defaults"
+	LayerBaseFile ConfigLayer = "base_file"
+	LayerEnvFile  ConfigLayer = "env_file"
+	LayerEnv      ConfigLayer = "env"
+	LayerFlag     ConfigLayer = "flag"
+)
+
+// envSeparator separates the nested keys on the names of the environment variables, as APP__DB__HOST for db.host
+const envSeparator = "__"
+
+// ConfigSource is where a value of a layered config came from, as the file, the environment variable or the flag
+type ConfigSource struct {
+	Layer ConfigLayer `json:"layer"`
+	Name  string      `json:"name,omitempty"`
+}
+
+// LayeredConfig merges the values of the defaults, the base file, the file of the environment,
+// the environment variables and the flags, where each one overrides the previous ones
+type LayeredConfig struct {
+	configAccessors
+	obj       interface{}
+	defaults  interface{}
+	baseFile  string
+	envFile   string
+	envPrefix string
+	flags     *flag.FlagSet
+	values    map[string]interface{}
+	sources   map[string]ConfigSource
+	viper     *viper.Viper
+	logger    logger.ILogger
+	mux       sync.RWMutex
+}
+
+// NewLayeredConfig loads the layers into the object, that should be a pointer
+func (manager *Manager) NewLayeredConfig(obj interface{}, options ...LayeredConfigOption) (*LayeredConfig, error) {
+	config := &LayeredConfig{
+		obj:    obj,
+		logger: manager.logger,
+	}
+	config.configAccessors = configAccessors{viper: config.getViper}
+	config.Reconfigure(options...)
+
+	if err := config.Reload(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Get ...
+func (config *LayeredConfig) Get(key string) interface{} {
+	return config.getViper().Get(key)
+}
+
+// GetString ...
+func (config *LayeredConfig) GetString(key string) string {
+	return config.getViper().GetString(key)
+}
+
+// GetBool ...
+func (config *LayeredConfig) GetBool(key string) bool {
+	return config.getViper().GetBool(key)
+}
+
+// GetInt ...
+func (config *LayeredConfig) GetInt(key string) int {
+	return config.getViper().GetInt(key)
+}
+
+// GetInt64 ...
+func (config *LayeredConfig) GetInt64(key string) int64 {
+	return config.getViper().GetInt64(key)
+}
+
+// GetFloat64 ...
+func (config *LayeredConfig) GetFloat64(key string) float64 {
+	return config.getViper().GetFloat64(key)
+}
+
+// GetTime ...
+func (config *LayeredConfig) GetTime(key string) time.Time {
+	return config.getViper().GetTime(key)
+}
+
+// GetDuration ...
+func (config *LayeredConfig) GetDuration(key string) time.Duration {
+	return config.getViper().GetDuration(key)
+}
+
+// GetStringSlice ...
+func (config *LayeredConfig) GetStringSlice(key string) []string {
+	return config.getViper().GetStringSlice(key)
+}
+
+// GetStringMap ...
+func (config *LayeredConfig) GetStringMap(key string) map[string]interface{} {
+	return config.getViper().GetStringMap(key)
+}
+
+// GetStringMapString ...
+func (config *LayeredConfig) GetStringMapString(key string) map[string]string {
+	return config.getViper().GetStringMapString(key)
+}
+
+// GetStringMapStringSlice ...
+func (config *LayeredConfig) GetStringMapStringSlice(key string) map[string][]string {
+	return config.getViper().GetStringMapStringSlice(key)
+}
+
+// GetObj ...
+func (config *LayeredConfig) GetObj() interface{} {
+	config.mux.RLock()
+	defer config.mux.RUnlock()
+
+	return config.obj
+}
+
+// Set ...
+func (config *LayeredConfig) Set(obj interface{}) {
+	config.mux.Lock()
+	defer config.mux.Unlock()
+
+	config.obj = obj
+}
+
+// Source returns where the value of the key came from, or false when the key isn't set
+func (config *LayeredConfig) Source(key string) (ConfigSource, bool) {
+	config.mux.RLock()
+	defer config.mux.RUnlock()
+
+	source, exists := config.sources[strings.ToLower(key)]
+	return source, exists
+}
+
+// Sources returns where each value came from, by key
+func (config *LayeredConfig) Sources() map[string]ConfigSource {
+	config.mux.RLock()
+	defer config.mux.RUnlock()
+
+	sources := make(map[string]ConfigSource, len(config.sources))
+	for key, source := range config.sources {
+		sources[key] = source
+	}
+
+	return sources
+}
+
+// Reload loads the layers again, except the flags that were already parsed, keeping the object when they are invalid
+func (config *LayeredConfig) Reload() error {
+	values := make(map[string]interface{})
+	sources := make(map[string]ConfigSource)
+
+	if config.defaults != nil {
+		defaults, err := toConfigMap(config.defaults)
+		if err != nil {
+			return fmt.Errorf("invalid config defaults: %s", err)
+		}
+		mergeConfig(values, defaults, "", ConfigSource{Layer: LayerDefaults}, sources)
+	}
+
+	if config.baseFile != "" {
+		file, err := readConfigFile(config.baseFile)
+		if err != nil {
+			return err
+		}
+		mergeConfig(values, file, "", ConfigSource{Layer: LayerBaseFile, Name: config.baseFile}, sources)
+	}
+
+	if config.envFile != "" {
+		name := fmt.Sprintf(config.envFile, GetEnv())
+		if Exists(name) || Exists(global[path_key].(string)+name) {
+			file, err := readConfigFile(name)
+			if err != nil {
+				return err
+			}
+			mergeConfig(values, file, "", ConfigSource{Layer: LayerEnvFile, Name: name}, sources)
+		}
+	}
+
+	// the text of the environment variables and the flags is converted to the type of the fields of the object
+	target := reflect.TypeOf(config.GetObj())
+
+	if config.envPrefix != "" {
+		prefix := config.envPrefix + envSeparator
+		for _, variable := range os.Environ() {
+			pair := strings.SplitN(variable, "=", 2)
+			if len(pair) != 2 || !strings.HasPrefix(pair[0], prefix) {
+				continue
+			}
+
+			path := strings.Split(strings.TrimPrefix(pair[0], prefix), envSeparator)
+			setConfigValue(values, path, pair[1], target, ConfigSource{Layer: LayerEnv, Name: pair[0]}, sources)
+		}
+	}
+
+	if config.flags != nil {
+		config.flags.Visit(func(f *flag.Flag) {
+			setConfigValue(values, strings.Split(f.Name, "."), f.Value.String(), target, ConfigSource{Layer: LayerFlag, Name: f.Name}, sources)
+		})
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	config.mux.Lock()
+	defer config.mux.Unlock()
+
+	if config.obj != nil {
+		obj := newConfigObj(config.obj)
+		if obj == nil {
+			return fmt.Errorf("the config object should be a pointer, got %T", config.obj)
+		}
+
+		if err = json.Unmarshal(data, obj); err != nil {
+			return fmt.Errorf("error loading the config layers: %s", err)
+		}
+
+		// the layers are only applied to the object when they are valid
+		if err = ValidateConfig(obj); err != nil {
+			return err
+		}
+
+		reflect.ValueOf(config.obj).Elem().Set(reflect.ValueOf(obj).Elem())
+	}
+
+	config.values = values
+	config.sources = sources
+	config.viper = loadViper(data)
+
+	return nil
+}
+
+// Save writes the object to the file of the environment, or to the base file when there isn't one,
+// and loads the layers again
+func (config *LayeredConfig) Save() error {
+	file := config.baseFile
+	if config.envFile != "" {
+		file = fmt.Sprintf(config.envFile, GetEnv())
+	}
+
+	if file == "" {
+		return fmt.Errorf("there isn't a config file to save")
+	}
+
+	if err := writeConfigFile(file, config.GetObj()); err != nil {
+		return err
+	}
+
+	return config.Reload()
+}
+
+func (config *LayeredConfig) getViper() *viper.Viper {
+	config.mux.RLock()
+	defer config.mux.RUnlock()
+
+	return config.viper
+}
+
+// readConfigFile reads a config file into a map, with the format of its extension
+func readConfigFile(name string) (map[string]interface{}, error) {
+	data, err := ReadFile(name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeConfigFile(name, data)
+}
+
+// toConfigMap converts a struct or a map to a map, through its json
+func toConfigMap(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+	if err = json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// normalizeConfigValue converts the maps of yaml, with keys of any type, to maps with string keys
+func normalizeConfigValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = normalizeConfigValue(item)
+		}
+		return m
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeConfigValue(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeConfigValue(item)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// mergeConfig merges the values of the layer into the config, keeping the source of each value
+func mergeConfig(values, layer map[string]interface{}, prefix string, source ConfigSource, sources map[string]ConfigSource) {
+	for key, value := range layer {
+		existingKey := findConfigKey(values, key)
+		path := strings.ToLower(prefix + existingKey)
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			current, ok := values[existingKey].(map[string]interface{})
+			if !ok {
+				current = make(map[string]interface{})
+				values[existingKey] = current
+			}
+			mergeConfig(current, nested, path+".", source, sources)
+			continue
+		}
+
+		values[existingKey] = value
+		sources[path] = source
+	}
+}
+
+// setConfigValue sets the value of the environment variable or the flag on the path,
+// converting it to the type of the field of the target, or of the value it replaces
+func setConfigValue(values map[string]interface{}, path []string, value string, target reflect.Type, source ConfigSource, sources map[string]ConfigSource) {
+	keys := setConfigPath(values, path, target, func(previous interface{}, target reflect.Type) interface{} {
+		return parseConfigValue(previous, value, target)
+	})
+
+	sources[strings.ToLower(strings.Join(keys, "."))] = source
+}
+
+// setConfigPath sets the value returned by parse on the path, given the value it replaces and the type of the field
+// of the target on the path, returning the keys of the path as they are on the values
+func setConfigPath(values map[string]interface{}, path []string, target reflect.Type, parse func(previous interface{}, target reflect.Type) interface{}) []string {
+	current := values
+	var keys []string
+	for i, key := range path {
+		target = configFieldType(target, key)
+		key = findConfigKey(current, strings.ToLower(key))
+		keys = append(keys, key)
+
+		if i == len(path)-1 {
+			current[key] = parse(current[key], target)
+			break
+		}
+
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			current[key] = next
+		}
+		current = next
+	}
+
+	return keys
+}
+
+// parseConfigValue converts the text to the type of the target field, or of the previous value,
+// or guesses it from the text when neither is known
+func parseConfigValue(previous interface{}, text string, target reflect.Type) interface{} {
+	if target != nil {
+		switch kind := target.Kind(); {
+		case kind == reflect.String:
+			return text
+		case kind == reflect.Bool:
+			if value, err := strconv.ParseBool(text); err == nil {
+				return value
+			}
+			return text
+		case kind >= reflect.Int && kind <= reflect.Float64:
+			return parseConfigNumber(text)
+		case kind == reflect.Slice || kind == reflect.Array:
+			return parseConfigList(text)
+		}
+	}
+
+	switch previous.(type) {
+	case string:
+		return text
+	case bool:
+		if value, err := strconv.ParseBool(text); err == nil {
+			return value
+		}
+	case float64, int, int64, json.Number:
+		return parseConfigNumber(text)
+	case []interface{}:
+		return parseConfigList(text)
+	case nil:
+		var value interface{}
+		if err := json.Unmarshal([]byte(text), &value); err == nil {
+			return value
+		}
+	}
+
+	return text
+}
+
+func parseConfigNumber(text string) interface{} {
+	if value, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return value
+	}
+	if value, err := strconv.ParseFloat(text, 64); err == nil {
+		return value
+	}
+
+	return text
+}
+
+// parseConfigList reads the text as a json array, or as a list separated by commas
+func parseConfigList(text string) []interface{} {
+	var value []interface{}
+	if err := json.Unmarshal([]byte(text), &value); err == nil {
+		return value
+	}
+
+	items := strings.Split(text, ",")
+	value = make([]interface{}, len(items))
+	for i, item := range items {
+		value[i] = strings.TrimSpace(item)
+	}
+
+	return value
+}
+
+// configFieldType returns the type of the field of the object with the given json key, regardless of the case,
+// or the type of the values of a map, and nil when it isn't known
+func configFieldType(typ reflect.Type, key string) reflect.Type {
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil {
+		return nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Map:
+		return typ.Elem()
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if field.PkgPath != "" && !field.Anonymous {
+				continue
+			}
+
+			name, embedded := configFieldName(field)
+			if embedded {
+				if fieldType := configFieldType(field.Type, key); fieldType != nil {
+					return fieldType
+				}
+				continue
+			}
+
+			if strings.EqualFold(name, key) {
+				return field.Type
+			}
+		}
+	}
+
+	return nil
+}
+
+// findConfigKey returns the key of the map that matches the given one regardless of the case, or the given one
+func findConfigKey(values map[string]interface{}, key string) string {
+	if _, exists := values[key]; exists {
+		return key
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if strings.EqualFold(k, key) {
+			return k
+		}
+	}
+
+	return key
+}
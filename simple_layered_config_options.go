@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"flag"
+)
+
+// LayeredConfigOption ...
+type LayeredConfigOption func(config *LayeredConfig)
+
+// Reconfigure ...
+func (config *LayeredConfig) Reconfigure(options ...LayeredConfigOption) {
+	for _, option := range options {
+		option(config)
+	}
+}
+
+// WithDefaults sets the lowest layer, as a struct of the type of the object with the default values
+func WithDefaults(defaults interface{}) LayeredConfigOption {
+	return func(config *LayeredConfig) {
+		config.defaults = defaults
+	}
+}
+
+// WithBaseFile sets the json, yaml, toml or .env file loaded over the defaults, that should exist
+func WithBaseFile(file string) LayeredConfigOption {
+	return func(config *LayeredConfig) {
+		config.baseFile = file
+	}
+}
+
+// WithEnvFile sets the file loaded over the base file, where %s is replaced by the environment,
+// as /config/app.%s.json, and that is skipped when it doesn't exist
+func WithEnvFile(pattern string) LayeredConfigOption {
+	return func(config *LayeredConfig) {
+		config.envFile = pattern
+	}
+}
+
+// WithEnvPrefix loads the environment variables with the prefix over the files,
+// where the nested keys are separated by __, as APP__DB__HOST for db.host
+func WithEnvPrefix(prefix string) LayeredConfigOption {
+	return func(config *LayeredConfig) {
+		config.envPrefix = prefix
+	}
+}
+
+// WithFlags loads the flags that were set over every other layer, where the nested keys are separated by dots,
+// as -db.host, so the flag set should be parsed before the config is created
+func WithFlags(flags *flag.FlagSet) LayeredConfigOption {
+	return func(config *LayeredConfig) {
+		config.flags = flags
+	}
+}
@@ -0,0 +1,96 @@
+package manager
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLayeredConfig(t *testing.T) {
+	type appConfig struct {
+		Name    string   `json:"name"`
+		Workers int      `json:"workers"`
+		Debug   bool     `json:"debug"`
+		Tags    []string `json:"tags"`
+		DB      struct {
+			Host     string `json:"host"`
+			Port     int    `json:"port"`
+			Password string `json:"password"`
+		} `json:"db"`
+	}
+
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "app.json"), []byte(`{"name": "base", "workers": 2, "db": {"host": "base", "port": 5432}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "app.test.yaml"), []byte("db:\n  host: test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("env", "test")
+	t.Setenv("APP__WORKERS", "8")
+	t.Setenv("APP__TAGS", "a, b")
+	t.Setenv("APP__DB__PORT", "6543")
+	// the text is kept for the fields of strings, even when no other layer sets it
+	t.Setenv("APP__DB__PASSWORD", "12345")
+
+	flags := flag.NewFlagSet("app", flag.ContinueOnError)
+	flags.Bool("debug", false, "")
+	flags.String("db.host", "", "")
+	flags.String("ignored", "", "")
+	if err := flags.Parse([]string{"-debug", "-db.host=flag"}); err != nil {
+		t.Fatal(err)
+	}
+
+	defaults := appConfig{Name: "default", Workers: 1, Tags: []string{}}
+	defaults.DB.Host = "default"
+
+	var obj appConfig
+	config, err := NewManager().NewLayeredConfig(&obj,
+		WithDefaults(defaults),
+		WithBaseFile(filepath.Join(dir, "app.json")),
+		WithEnvFile(filepath.Join(dir, "app.%s.yaml")),
+		WithEnvPrefix("APP"),
+		WithFlags(flags))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if obj.Name != "base" || obj.Workers != 8 || !obj.Debug || fmt.Sprint(obj.Tags) != "[a b]" || obj.DB.Host != "flag" || obj.DB.Port != 6543 || obj.DB.Password != "12345" {
+		t.Fatalf("unexpected merged object %+v", obj)
+	}
+
+	if config.GetString("name") != "base" || config.GetInt("workers") != 8 || config.GetString("db.host") != "flag" || config.GetInt("db.port") != 6543 {
+		t.Fatalf("unexpected merged values %v", config.GetStringMap("db"))
+	}
+
+	expected := map[string]ConfigSource{
+		"name":    {Layer: LayerBaseFile, Name: filepath.Join(dir, "app.json")},
+		"workers": {Layer: LayerEnv, Name: "APP__WORKERS"},
+		"debug":   {Layer: LayerFlag, Name: "debug"},
+		"db.host": {Layer: LayerFlag, Name: "db.host"},
+		"db.port": {Layer: LayerEnv, Name: "APP__DB__PORT"},
+	}
+	for key, source := range expected {
+		if got, _ := config.Source(key); got != source {
+			t.Fatalf("expected the source of %s to be %+v, got %+v", key, source, got)
+		}
+	}
+
+	flags.Set("db.host", "")
+	t.Setenv("APP__DB__PORT", "7654")
+	os.Unsetenv("APP__WORKERS")
+	if err := config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if obj.Workers != 2 || obj.DB.Port != 7654 {
+		t.Fatalf("expected the reloaded layers, got %+v", obj)
+	}
+	if source, _ := config.Source("workers"); source.Layer != LayerBaseFile {
+		t.Fatalf("expected the workers from the base file, got %+v", source)
+	}
+}
@@ -0,0 +1,368 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/joaosoft/logger"
+)
+
+const (
+	defaultLeaseTTL = 15 * time.Second
+	leaseKeyPrefix  = "manager:leader:"
+)
+
+// LeaderElection elects a single leader between the replicas of a service, through a lease on a redis key.
+// the lease is taken with SET NX PX, renewed while the replica is the leader and released when the election is stopped,
+// so that another replica takes it over, where the renew and the release only change the lease held by the replica
+type LeaderElection struct {
+	name          string
+	key           string
+	id            string
+	lease         IRedisLease
+	ttl           time.Duration
+	renewInterval time.Duration
+	logger        logger.ILogger
+
+	leader    bool
+	handlers  []func(leader bool)
+	processes map[*LeaderOnlyProcess]bool
+	cancel    context.CancelFunc
+	done      chan struct{}
+	mux       sync.Mutex
+	state     lifecycleState
+}
+
+// NewLeaderElection creates the election on the redis, that should hold the leases atomically, as the SimpleRedis
+func (manager *Manager) NewLeaderElection(name string, redis IRedis, options ...LeaderElectionOption) *LeaderElection {
+	hostname, _ := os.Hostname()
+
+	lease, _ := redis.(IRedisLease)
+
+	election := &LeaderElection{
+		name:      name,
+		key:       leaseKeyPrefix + name,
+		id:        fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), rand.Int63()),
+		lease:     lease,
+		ttl:       defaultLeaseTTL,
+		logger:    manager.logger,
+		processes: make(map[*LeaderOnlyProcess]bool),
+	}
+	election.Reconfigure(options...)
+
+	if election.renewInterval <= 0 {
+		election.renewInterval = election.ttl / 3
+	}
+
+	return election
+}
+
+// Start ...
+func (election *LeaderElection) Start(ctx context.Context) error {
+	election.state.lock()
+	defer election.state.unlock()
+
+	if election.state.isStarted() {
+		return nil
+	}
+
+	if election.lease == nil {
+		return fmt.Errorf("the redis of the leader election %s can't hold leases atomically", election.name)
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	election.mux.Lock()
+	election.cancel = cancel
+	election.done = done
+	election.mux.Unlock()
+
+	// try to take the lease before the leader only processes are started
+	election.campaign()
+
+	go election.run(runCtx, done)
+	election.state.setStarted(true)
+
+	return nil
+}
+
+// Stop releases the lease when the replica is the leader, handing it over to another replica
+func (election *LeaderElection) Stop(ctx context.Context) error {
+	election.state.lock()
+	defer election.state.unlock()
+
+	if !election.state.isStarted() {
+		return nil
+	}
+
+	election.mux.Lock()
+	cancel, done := election.cancel, election.done
+	election.mux.Unlock()
+
+	cancel()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("timeout waiting for the leader election %s to stop: %s", election.name, ctx.Err())
+	}
+
+	if election.IsLeader() {
+		election.setLeader(false)
+
+		if err := election.release(); err != nil {
+			election.logger.Errorf("error releasing the lease [ name: %s ]: %s", election.name, err)
+		}
+	}
+
+	election.state.setStarted(false)
+
+	return nil
+}
+
+// Started ...
+func (election *LeaderElection) Started() bool {
+	return election.state.isStarted()
+}
+
+// IsLeader ...
+func (election *LeaderElection) IsLeader() bool {
+	election.mux.Lock()
+	defer election.mux.Unlock()
+
+	return election.leader
+}
+
+// Id returns the id of the replica on the election
+func (election *LeaderElection) Id() string {
+	return election.id
+}
+
+// OnLeadership subscribes the handler to the changes of the leadership of the replica
+func (election *LeaderElection) OnLeadership(handler func(leader bool)) {
+	election.mux.Lock()
+	defer election.mux.Unlock()
+
+	election.handlers = append(election.handlers, handler)
+}
+
+// run renews or takes the lease on every interval, until the context is done
+func (election *LeaderElection) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(election.renewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if election.IsLeader() {
+			if err := election.renew(); err != nil {
+				election.logger.Errorf("leadership lost [ name: %s ]: %s", election.name, err)
+				election.setLeader(false)
+			}
+		} else {
+			election.campaign()
+		}
+	}
+}
+
+// campaign takes the lease when it's free
+func (election *LeaderElection) campaign() {
+	acquired, err := election.lease.AcquireLease(election.key, []byte(election.id), election.ttl)
+	if err != nil {
+		election.logger.Errorf("error taking the lease [ name: %s ]: %s", election.name, err)
+		return
+	}
+
+	if !acquired {
+		return
+	}
+
+	election.logger.Infof("leadership acquired [ name: %s, id: %s ]", election.name, election.id)
+	election.setLeader(true)
+}
+
+// renew extends the lease, failing when it expired or is held by another replica
+func (election *LeaderElection) renew() error {
+	renewed, err := election.lease.RenewLease(election.key, []byte(election.id), election.ttl)
+	if err != nil {
+		return err
+	}
+
+	if !renewed {
+		return fmt.Errorf("the lease expired or is held by another replica")
+	}
+
+	return nil
+}
+
+// release deletes the lease when it's still held by the replica
+func (election *LeaderElection) release() error {
+	released, err := election.lease.ReleaseLease(election.key, []byte(election.id))
+	if err != nil {
+		return err
+	}
+
+	if released {
+		election.logger.Infof("leadership released [ name: %s, id: %s ]", election.name, election.id)
+	}
+
+	return nil
+}
+
+// setLeader changes the leadership, starting or stopping the leader only processes
+func (election *LeaderElection) setLeader(leader bool) {
+	election.mux.Lock()
+	if election.leader == leader {
+		election.mux.Unlock()
+		return
+	}
+
+	election.leader = leader
+	handlers := make([]func(leader bool), len(election.handlers))
+	copy(handlers, election.handlers)
+
+	processes := make([]*LeaderOnlyProcess, 0, len(election.processes))
+	for process := range election.processes {
+		processes = append(processes, process)
+	}
+	election.mux.Unlock()
+
+	for _, process := range processes {
+		process.setLeader(leader)
+	}
+
+	for _, handler := range handlers {
+		handler(leader)
+	}
+}
+
+func (election *LeaderElection) subscribe(process *LeaderOnlyProcess) bool {
+	election.mux.Lock()
+	defer election.mux.Unlock()
+
+	election.processes[process] = true
+	return election.leader
+}
+
+func (election *LeaderElection) unsubscribe(process *LeaderOnlyProcess) {
+	election.mux.Lock()
+	defer election.mux.Unlock()
+
+	delete(election.processes, process)
+}
+
+// LeaderOnlyProcess runs a process only while the replica is the leader of the election.
+// it's started with the manager, but its process is started when the leadership is acquired
+// and stopped when it's lost
+type LeaderOnlyProcess struct {
+	election       *LeaderElection
+	process        ILifecycle
+	logger         logger.ILogger
+	processStarted bool
+	mux            sync.Mutex
+	state          lifecycleState
+}
+
+// NewLeaderOnlyProcess wraps the process to run only on the leader, where the process should depend on the election
+func (manager *Manager) NewLeaderOnlyProcess(election *LeaderElection, process IProcess) (*LeaderOnlyProcess, error) {
+	lifecycle, err := lifecycleOf(process)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LeaderOnlyProcess{
+		election: election,
+		process:  lifecycle,
+		logger:   manager.logger,
+	}, nil
+}
+
+// Start ...
+func (process *LeaderOnlyProcess) Start(ctx context.Context) error {
+	process.state.lock()
+	defer process.state.unlock()
+
+	if process.state.isStarted() {
+		return nil
+	}
+
+	process.state.setStarted(true)
+	if process.election.subscribe(process) {
+		process.setLeader(true)
+	}
+
+	return nil
+}
+
+// Stop ...
+func (process *LeaderOnlyProcess) Stop(ctx context.Context) error {
+	process.state.lock()
+	defer process.state.unlock()
+
+	if !process.state.isStarted() {
+		return nil
+	}
+
+	process.election.unsubscribe(process)
+
+	process.mux.Lock()
+	defer process.mux.Unlock()
+
+	if process.processStarted {
+		if err := process.process.Stop(ctx); err != nil {
+			return err
+		}
+		process.processStarted = false
+	}
+
+	process.state.setStarted(false)
+
+	return nil
+}
+
+// Started ...
+func (process *LeaderOnlyProcess) Started() bool {
+	return process.state.isStarted()
+}
+
+// Leading returns if the process is running on this replica
+func (process *LeaderOnlyProcess) Leading() bool {
+	process.mux.Lock()
+	defer process.mux.Unlock()
+
+	return process.processStarted
+}
+
+// setLeader starts the process when the leadership is acquired and stops it when it's lost
+func (process *LeaderOnlyProcess) setLeader(leader bool) {
+	process.mux.Lock()
+	defer process.mux.Unlock()
+
+	if !process.state.isStarted() || process.processStarted == leader {
+		return
+	}
+
+	var err error
+	if leader {
+		err = process.process.Start(context.Background())
+	} else {
+		err = process.process.Stop(context.Background())
+	}
+
+	if err != nil {
+		process.logger.Errorf("error changing the leadership of the process [ leader: %t ]: %s", leader, err)
+		return
+	}
+
+	process.processStarted = leader
+}
@@ -0,0 +1,36 @@
+package manager
+
+import (
+	"time"
+)
+
+// LeaderElectionOption ...
+type LeaderElectionOption func(election *LeaderElection)
+
+// Reconfigure ...
+func (election *LeaderElection) Reconfigure(options ...LeaderElectionOption) {
+	for _, option := range options {
+		option(election)
+	}
+}
+
+// WithLeaseTTL sets the time to live of the lease, with a precision of milliseconds, after which a leader that stopped renewing it is replaced
+func WithLeaseTTL(ttl time.Duration) LeaderElectionOption {
+	return func(election *LeaderElection) {
+		election.ttl = ttl
+	}
+}
+
+// WithRenewInterval sets the interval to renew or take the lease, a third of the time to live by default
+func WithRenewInterval(interval time.Duration) LeaderElectionOption {
+	return func(election *LeaderElection) {
+		election.renewInterval = interval
+	}
+}
+
+// WithLeaderId sets the id of the replica on the election, made of the hostname and the pid by default
+func WithLeaderId(id string) LeaderElectionOption {
+	return func(election *LeaderElection) {
+		election.id = id
+	}
+}
@@ -0,0 +1,319 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLeaderElection(t *testing.T) {
+	redis := newMemoryRedis()
+
+	var managers []*Manager
+	var processes []*LeaderOnlyProcess
+	for i := 0; i < 2; i++ {
+		manager := newTestManager()
+
+		election := manager.NewLeaderElection("singleton", redis, WithLeaderId(fmt.Sprintf("replica_%d", i)), WithRenewInterval(5*time.Millisecond))
+		process, err := manager.NewLeaderOnlyProcess(election, manager.NewScheduledProcess("singleton", Every(time.Hour), func(ctx context.Context) error { return nil }))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := manager.AddProcess("election", election); err != nil {
+			t.Fatal(err)
+		}
+		if err := manager.AddProcess("singleton", process); err != nil {
+			t.Fatal(err)
+		}
+		if err := manager.AddDependency(KindProcess, "singleton", NewComponentId(KindProcess, "election")); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := manager.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		managers = append(managers, manager)
+		processes = append(processes, process)
+	}
+
+	if !processes[0].Leading() || processes[1].Leading() {
+		t.Fatal("expected the process to run only on the first replica")
+	}
+
+	// the lease is handed over on a graceful shutdown
+	if err := managers[0].Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !processes[1].Leading() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the second replica to take over the leadership")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if processes[0].Leading() {
+		t.Fatal("expected the process to be stopped on the first replica")
+	}
+
+	if err := managers[1].Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, _ := redis.Get(leaseKeyPrefix + "singleton"); value != nil {
+		t.Fatalf("expected the lease to be released, got %s", value)
+	}
+}
+
+func TestLeaderElectionSplitBrain(t *testing.T) {
+	redis := newMemoryRedis()
+	manager := newTestManager()
+	key := leaseKeyPrefix + "split"
+
+	first := manager.NewLeaderElection("split", redis, WithLeaderId("first"), WithLeaseTTL(time.Hour), WithRenewInterval(time.Hour))
+	if err := first.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !first.IsLeader() {
+		t.Fatal("expected the first replica to be the leader")
+	}
+
+	// the first replica is paused past its lease, as on a long gc pause, and the second one takes it over
+	redis.mux.Lock()
+	redis.expires[key] = time.Now().Add(-time.Millisecond)
+	redis.mux.Unlock()
+
+	second := manager.NewLeaderElection("split", redis, WithLeaderId("second"), WithLeaseTTL(time.Hour), WithRenewInterval(time.Hour))
+	if err := second.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !second.IsLeader() {
+		t.Fatal("expected the second replica to take over the expired lease")
+	}
+
+	redis.mux.Lock()
+	expires := redis.expires[key]
+	redis.mux.Unlock()
+
+	// when the first replica resumes, it can't renew nor release the lease of the second one
+	if err := first.renew(); err == nil {
+		t.Fatal("expected the renew of the first replica to fail")
+	}
+
+	if err := first.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	redis.mux.Lock()
+	value, renewed := string(redis.values[key]), !redis.expires[key].Equal(expires)
+	redis.mux.Unlock()
+
+	if value != "second" {
+		t.Fatalf("expected the lease to be held by the second replica, got %q", value)
+	}
+	if renewed {
+		t.Fatal("expected the lease of the second replica to be left as it was")
+	}
+
+	if err := second.renew(); err != nil {
+		t.Fatalf("expected the second replica to renew its lease: %s", err)
+	}
+
+	if err := second.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, _ := redis.Get(key); value != nil {
+		t.Fatalf("expected the lease to be released, got %s", value)
+	}
+}
+
+func TestLeaderElectionWithoutLeases(t *testing.T) {
+	manager := newTestManager()
+
+	election := manager.NewLeaderElection("plain", struct{ IRedis }{})
+	if err := election.Start(context.Background()); err == nil {
+		t.Fatal("expected an error starting the election on a redis without atomic leases")
+	}
+	if election.Started() {
+		t.Fatal("expected the election to be stopped")
+	}
+}
+
+// memoryRedis is an in-memory stand-in of the redis commands used by the leader election and the remote config
+type memoryRedis struct {
+	IRedis
+	values      map[string][]byte
+	expires     map[string]time.Time
+	hashes      map[string]map[string][]byte
+	subscribers map[string][]RedisMessageHandler
+	stopped     bool
+	mux         sync.Mutex
+}
+
+func newMemoryRedis() *memoryRedis {
+	return &memoryRedis{
+		values:      make(map[string][]byte),
+		expires:     make(map[string]time.Time),
+		hashes:      make(map[string]map[string][]byte),
+		subscribers: make(map[string][]RedisMessageHandler),
+	}
+}
+
+func (redis *memoryRedis) expire(key string) {
+	if expires, exists := redis.expires[key]; exists && time.Now().After(expires) {
+		delete(redis.values, key)
+		delete(redis.expires, key)
+	}
+}
+
+func (redis *memoryRedis) AcquireLease(key string, value []byte, ttl time.Duration) (bool, error) {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	redis.expire(key)
+	if _, exists := redis.values[key]; exists {
+		return false, nil
+	}
+	redis.values[key] = value
+	redis.expires[key] = time.Now().Add(ttl)
+
+	return true, nil
+}
+
+func (redis *memoryRedis) RenewLease(key string, value []byte, ttl time.Duration) (bool, error) {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	redis.expire(key)
+	if current, exists := redis.values[key]; !exists || !bytes.Equal(current, value) {
+		return false, nil
+	}
+	redis.expires[key] = time.Now().Add(ttl)
+
+	return true, nil
+}
+
+func (redis *memoryRedis) ReleaseLease(key string, value []byte) (bool, error) {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	redis.expire(key)
+	if current, exists := redis.values[key]; !exists || !bytes.Equal(current, value) {
+		return false, nil
+	}
+	delete(redis.values, key)
+	delete(redis.expires, key)
+
+	return true, nil
+}
+
+func (redis *memoryRedis) Get(key string) ([]byte, error) {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	redis.expire(key)
+	return redis.values[key], nil
+}
+
+func (redis *memoryRedis) Del(key string) (bool, error) {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	_, exists := redis.values[key]
+	_, hashExists := redis.hashes[key]
+	delete(redis.values, key)
+	delete(redis.expires, key)
+	delete(redis.hashes, key)
+
+	return exists || hashExists, nil
+}
+
+func (redis *memoryRedis) Started() bool {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	return !redis.stopped
+}
+
+func (redis *memoryRedis) Set(key string, value []byte) error {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	redis.values[key] = value
+	return nil
+}
+
+func (redis *memoryRedis) Hset(key, field string, value []byte) error {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	if redis.hashes[key] == nil {
+		redis.hashes[key] = make(map[string][]byte)
+	}
+	redis.hashes[key][field] = value
+
+	return nil
+}
+
+func (redis *memoryRedis) Rename(key, newKey string) error {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	value, exists := redis.values[key]
+	hash, hashExists := redis.hashes[key]
+	if !exists && !hashExists {
+		return fmt.Errorf("no such key")
+	}
+
+	delete(redis.values, newKey)
+	delete(redis.hashes, newKey)
+	if exists {
+		redis.values[newKey] = value
+		delete(redis.values, key)
+	}
+	if hashExists {
+		redis.hashes[newKey] = hash
+		delete(redis.hashes, key)
+	}
+
+	return nil
+}
+
+func (redis *memoryRedis) Hgetall(key string) ([][]byte, error) {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	var pairs [][]byte
+	for field, value := range redis.hashes[key] {
+		pairs = append(pairs, []byte(field), value)
+	}
+
+	return pairs, nil
+}
+
+func (redis *memoryRedis) Publish(channel string, message []byte) (int64, error) {
+	redis.mux.Lock()
+	subscribers := append([]RedisMessageHandler(nil), redis.subscribers[channel]...)
+	redis.mux.Unlock()
+
+	for _, handler := range subscribers {
+		go handler(message)
+	}
+
+	return int64(len(subscribers)), nil
+}
+
+func (redis *memoryRedis) Subscribe(ctx context.Context, channel string, handler RedisMessageHandler) error {
+	redis.mux.Lock()
+	defer redis.mux.Unlock()
+
+	redis.subscribers[channel] = append(redis.subscribers[channel], handler)
+	return nil
+}
@@ -5,7 +5,7 @@ import (
 	"github.com/joaosoft/logger"
 	"time"
 
-	"sync"
+	"context"
 
 	"github.com/nsqio/go-nsq"
 )
@@ -14,39 +14,54 @@ import (
 type SimpleNSQConsumer struct {
 	client  *nsq.Consumer
 	handler INSQHandler
-	logger logger.ILogger
+	logger  logger.ILogger
 	config  *NSQConfig
-	started bool
+	metrics *messageMetrics
+	paused  bool
+	stopped bool
+	state   lifecycleState
 }
 
 // NewSimpleNSQConsumer ...
 func (manager *Manager) NewSimpleNSQConsumer(config *NSQConfig, handler INSQHandler) (INSQConsumer, error) {
 	manager.logger.Infof("nsq consumer, creating consumer [ topic: %s, channel: %s ]", config.Topic, config.Channel)
 
-	// Creating nsq configuration
-	nsqConfig := nsq.NewConfig()
-	nsqConfig.MaxAttempts = config.MaxAttempts
-	nsqConfig.DefaultRequeueDelay = time.Duration(config.RequeueDelay) * time.Second
-	nsqConfig.MaxInFlight = config.MaxInFlight
-	nsqConfig.ReadTimeout = 120 * time.Second
-
-	nsqConsumer, err := nsq.NewConsumer(config.Topic, config.Channel, nsqConfig)
-	nsqConsumer.AddHandler(handler)
-	if err != nil {
-		panic(err)
-	}
-
 	consumer := &SimpleNSQConsumer{
-		client:  nsqConsumer,
 		config:  config,
 		handler: handler,
+		metrics: newMessageMetrics(manager.metrics, "manager_nsq_consumer", Labels{"topic": config.Topic, "channel": config.Channel}, true, true),
+		logger:  manager.logger,
+	}
+
+	client, err := consumer.newClient()
+	if err != nil {
+		return nil, err
 	}
+	consumer.client = client
 
 	manager.logger.Infof("nsq consumer, consumer [ topic: %s, channel: %s ] created", config.Topic, config.Channel)
 
 	return consumer, nil
 }
 
+// newClient creates the nsq consumer, as a stopped nsq consumer can't connect again
+func (consumer *SimpleNSQConsumer) newClient() (*nsq.Consumer, error) {
+	// Creating nsq configuration
+	nsqConfig := nsq.NewConfig()
+	nsqConfig.MaxAttempts = consumer.config.MaxAttempts
+	nsqConfig.DefaultRequeueDelay = time.Duration(consumer.config.RequeueDelay) * time.Second
+	nsqConfig.MaxInFlight = consumer.config.MaxInFlight
+	nsqConfig.ReadTimeout = 120 * time.Second
+
+	client, err := nsq.NewConsumer(consumer.config.Topic, consumer.config.Channel, nsqConfig)
+	if err != nil {
+		return nil, err
+	}
+	client.AddHandler(nsq.HandlerFunc(consumer.instrument))
+
+	return client, nil
+}
+
 // HandleMessage ...
 func (consumer *SimpleNSQConsumer) HandleMessage(message *nsq.Message) error {
 	message.DisableAutoResponse()
@@ -58,25 +73,37 @@ func (consumer *SimpleNSQConsumer) HandleMessage(message *nsq.Message) error {
 	return nil
 }
 
+// instrument handles the message with the handler, counting the messages and the automatic responses
+func (consumer *SimpleNSQConsumer) instrument(message *nsq.Message) error {
+	consumer.metrics.messages.Inc()
+
+	err := consumer.handler.HandleMessage(message)
+	if err != nil {
+		consumer.metrics.errors.Inc()
+	}
+
+	if !message.IsAutoResponseDisabled() {
+		if err != nil {
+			consumer.metrics.nacks.Inc()
+		} else {
+			consumer.metrics.acks.Inc()
+		}
+	}
+
+	return err
+}
+
 // Stop ...
 func (consumer *SimpleNSQConsumer) Started() bool {
-	return consumer.started
+	return consumer.state.isStarted()
 }
 
 // Start ...
-func (consumer *SimpleNSQConsumer) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
+func (consumer *SimpleNSQConsumer) Start(ctx context.Context) error {
+	consumer.state.lock()
+	defer consumer.state.unlock()
 
-	if consumer.started {
+	if consumer.state.isStarted() {
 		return nil
 	}
 
@@ -84,6 +111,15 @@ func (consumer *SimpleNSQConsumer) Start(waitGroup ...*sync.WaitGroup) error {
 		return fmt.Errorf("nsq consumer, no handler configured")
 	}
 
+	// the consumer stopped by the previous stop or drain is replaced by a new one
+	if consumer.stopped {
+		client, err := consumer.newClient()
+		if err != nil {
+			return err
+		}
+		consumer.client, consumer.stopped, consumer.paused = client, false, false
+	}
+
 	if consumer.config.Lookupd != nil && len(consumer.config.Lookupd) > 0 {
 		for _, addr := range consumer.config.Lookupd {
 			consumer.logger.Infof("nsq consumer, consumer connecting to %s", addr)
@@ -104,36 +140,82 @@ func (consumer *SimpleNSQConsumer) Start(waitGroup ...*sync.WaitGroup) error {
 		}
 	}
 
-	if !consumer.started {
-		panic("nsq consumer, failed to start consumer")
+	consumer.state.setStarted(true)
+
+	return nil
+}
+
+// Drain stops receiving new messages and waits for the messages in flight
+func (consumer *SimpleNSQConsumer) Drain(ctx context.Context) error {
+	consumer.state.lock()
+	defer consumer.state.unlock()
+
+	if !consumer.state.isStarted() {
+		return nil
+	}
+
+	consumer.client.Stop()
+	consumer.stopped = true
+
+	select {
+	case <-consumer.client.StopChan:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+}
+
+// Stop ...
+func (consumer *SimpleNSQConsumer) Stop(ctx context.Context) error {
+	consumer.state.lock()
+	defer consumer.state.unlock()
 
-	consumer.started = true
+	if !consumer.state.isStarted() {
+		return nil
+	}
 
-	<-consumer.client.StopChan
+	consumer.client.Stop()
+	consumer.stopped = true
+	consumer.state.setStarted(false)
+
+	// wait for the messages in flight
+	select {
+	case <-consumer.client.StopChan:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 
 	return nil
 }
 
-// Stop ...
-func (consumer *SimpleNSQConsumer) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
+// Pause stops receiving new messages, without closing the connections
+func (consumer *SimpleNSQConsumer) Pause() error {
+	consumer.state.lock()
+	defer consumer.state.unlock()
 
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
+	if !consumer.state.isStarted() {
+		return fmt.Errorf("nsq consumer, not started")
 	}
 
-	defer wg.Done()
+	consumer.client.ChangeMaxInFlight(0)
+	consumer.paused = true
 
-	if !consumer.started {
-		return nil
+	return nil
+}
+
+// Resume ...
+func (consumer *SimpleNSQConsumer) Resume() error {
+	consumer.state.lock()
+	defer consumer.state.unlock()
+
+	if !consumer.state.isStarted() {
+		return fmt.Errorf("nsq consumer, not started")
 	}
 
-	consumer.client.Stop()
-	consumer.started = false
+	if consumer.paused {
+		consumer.client.ChangeMaxInFlight(consumer.config.MaxInFlight)
+		consumer.paused = false
+	}
 
 	return nil
 }
@@ -0,0 +1,41 @@
+package manager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nsqio/go-nsq"
+)
+
+type testNSQHandler struct{}
+
+func (handler *testNSQHandler) HandleMessage(message *nsq.Message) error {
+	return nil
+}
+
+func TestSimpleNSQConsumerRestart(t *testing.T) {
+	consumer, err := newTestManager().NewSimpleNSQConsumer(NewNSQConfig("topic", "channel", nil, nil, 1, 1), &testNSQHandler{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	simple := consumer.(*SimpleNSQConsumer)
+	if err := simple.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	first := simple.client
+
+	if err := simple.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// a stopped nsq consumer can't connect again, so the start takes a new one
+	if err := simple.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer simple.Stop(context.Background())
+
+	if simple.client == first || !simple.Started() {
+		t.Fatal("expected a new nsq consumer on the restart")
+	}
+}
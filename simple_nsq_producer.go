@@ -5,7 +5,7 @@ import (
 	"github.com/joaosoft/logger"
 	"time"
 
-	"sync"
+	"context"
 
 	"github.com/nsqio/go-nsq"
 )
@@ -13,9 +13,10 @@ import (
 // Producer ...
 type SimpleNSQProducer struct {
 	client  *nsq.Producer
-	logger logger.ILogger
+	logger  logger.ILogger
 	config  *NSQConfig
-	started bool
+	metrics *Metrics
+	state   lifecycleState
 }
 
 // NewSimpleNSQProducer ...
@@ -42,17 +43,24 @@ func (manager *Manager) NewSimpleNSQProducer(config *NSQConfig) (INSQProducer, e
 	}
 
 	producer := &SimpleNSQProducer{
-		client: nsqProducer,
-		config: config,
-		logger: manager.logger,
+		client:  nsqProducer,
+		config:  config,
+		metrics: manager.metrics,
+		logger:  manager.logger,
 	}
 
 	return producer, nil
 }
 
 // Publish ...
-func (producer *SimpleNSQProducer) Publish(topic string, body []byte, maxRetries int) error {
-	var err error
+func (producer *SimpleNSQProducer) Publish(topic string, body []byte, maxRetries int) (err error) {
+	metrics := newMessageMetrics(producer.metrics, "manager_nsq_producer", Labels{"topic": topic}, false, false)
+	metrics.messages.Inc()
+	defer func() {
+		if err != nil {
+			metrics.errors.Inc()
+		}
+	}()
 
 	for count := 0; count < maxRetries; count++ {
 		if err = producer.client.Publish(topic, body); err == nil {
@@ -64,46 +72,30 @@ func (producer *SimpleNSQProducer) Publish(topic string, body []byte, maxRetries
 }
 
 // Start ...
-func (producer *SimpleNSQProducer) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
+func (producer *SimpleNSQProducer) Start(ctx context.Context) error {
+	producer.state.lock()
+	defer producer.state.unlock()
 
-	if producer.started {
+	if producer.state.isStarted() {
 		return nil
 	}
 
-	producer.started = true
+	producer.state.setStarted(true)
 
 	return nil
 }
 
 // Stop ...
-func (producer *SimpleNSQProducer) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
+func (producer *SimpleNSQProducer) Stop(ctx context.Context) error {
+	producer.state.lock()
+	defer producer.state.unlock()
 
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if !producer.started {
+	if !producer.state.isStarted() {
 		return nil
 	}
 
 	producer.client.Stop()
-	producer.started = false
+	producer.state.setStarted(false)
 
 	return nil
 }
@@ -113,6 +105,11 @@ func (producer *SimpleNSQProducer) Started() bool {
 	return true
 }
 
+// Healthy ...
+func (producer *SimpleNSQProducer) Healthy(ctx context.Context) error {
+	return runWithContext(ctx, producer.client.Ping)
+}
+
 // Ping ...
 func (producer *SimpleNSQProducer) Ping() error {
 	return producer.client.Ping()
@@ -1,74 +1,68 @@
 package manager
 
 import (
+	"context"
 	"github.com/joaosoft/logger"
-	"sync"
 )
 
 // SimpleProcess ...
 type SimpleProcess struct {
 	function func() error
-	logger logger.ILogger
-	started  bool
+	logger   logger.ILogger
+	state    lifecycleState
 }
 
 // NewSimpleProcess...
 func (manager *Manager) NewSimpleProcess(function func() error) IProcess {
 	return &SimpleProcess{
 		function: function,
-		logger: manager.logger,
+		logger:   manager.logger,
 	}
 }
 
 // Start ...
-func (process *SimpleProcess) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
+func (process *SimpleProcess) Start(ctx context.Context) error {
+	process.state.lock()
+	defer process.state.unlock()
 
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if process.started {
+	if process.state.isStarted() {
 		return nil
 	}
 
-	if err := process.function(); err != nil {
-		return err
+	done := make(chan error, 1)
+	go func() {
+		done <- process.function()
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 
-	process.started = true
+	process.state.setStarted(true)
 
 	return nil
 }
 
 // Stop ...
-func (process *SimpleProcess) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
+func (process *SimpleProcess) Stop(ctx context.Context) error {
+	process.state.lock()
+	defer process.state.unlock()
 
-	if !process.started {
+	if !process.state.isStarted() {
 		return nil
 	}
 
-	process.started = false
+	process.state.setStarted(false)
 
 	return nil
 }
 
 // Started ...
 func (process *SimpleProcess) Started() bool {
-	return process.started
+	return process.state.isStarted()
 }
@@ -0,0 +1,37 @@
+package manager
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentComponentLifecycle(t *testing.T) {
+	manager := newTestManager()
+	process := manager.NewSimpleProcess(func() error { return nil }).(*SimpleProcess)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(3)
+
+		go func() {
+			defer wg.Done()
+			if err := process.Start(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			if err := process.Stop(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			process.Started()
+		}()
+	}
+	wg.Wait()
+}
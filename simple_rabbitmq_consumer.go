@@ -1,26 +1,37 @@
 package manager
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/joaosoft/logger"
-	"sync"
 
 	"github.com/streadway/amqp"
 )
 
+// defaultRabbitmqPauseTimeout is how long the pause waits for the deliveries in flight
+const defaultRabbitmqPauseTimeout = 30 * time.Second
+
 type SimpleRabbitmqConsumer struct {
 	config     *RabbitmqConfig
 	connection *amqp.Connection
+	closed     chan *amqp.Error
 	channel    *amqp.Channel
 	queue      string
 	bindingKey string
 	tag        string
 	handler    RabbitmqHandler
-	logger logger.ILogger
+	logger     logger.ILogger
 	done       chan error
-	started    bool
+	metrics    *messageMetrics
+	canceled   bool
+	paused     bool
+	state      lifecycleState
 }
 
 func (manager *Manager) NewSimpleRabbitmqConsumer(config *RabbitmqConfig, queue, bindingKey, tag string, handler RabbitmqHandler) (*SimpleRabbitmqConsumer, error) {
+	// every delivery is acknowledged, even when the handler fails, so there aren't nacks
 	consumer := &SimpleRabbitmqConsumer{
 		config:     config,
 		connection: nil,
@@ -29,26 +40,18 @@ func (manager *Manager) NewSimpleRabbitmqConsumer(config *RabbitmqConfig, queue,
 		bindingKey: bindingKey,
 		tag:        tag,
 		handler:    handler,
-		logger: manager.logger,
-		done:       make(chan error),
+		metrics:    newMessageMetrics(manager.metrics, "manager_rabbitmq_consumer", Labels{"exchange": config.Exchange, "queue": queue}, true, false),
+		logger:     manager.logger,
 	}
 
 	return consumer, nil
 }
 
-func (consumer *SimpleRabbitmqConsumer) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
+func (consumer *SimpleRabbitmqConsumer) Start(ctx context.Context) error {
+	consumer.state.lock()
+	defer consumer.state.unlock()
 
-	if consumer.started {
+	if consumer.state.isStarted() {
 		return nil
 	}
 
@@ -58,6 +61,7 @@ func (consumer *SimpleRabbitmqConsumer) Start(waitGroup ...*sync.WaitGroup) erro
 		err = consumer.logger.Errorf("dial: %s", err).ToError()
 		return err
 	}
+	consumer.closed = consumer.connection.NotifyClose(make(chan *amqp.Error, 1))
 
 	defer func(err error) {
 		if err != nil {
@@ -78,11 +82,11 @@ func (consumer *SimpleRabbitmqConsumer) Start(waitGroup ...*sync.WaitGroup) erro
 	if err = consumer.channel.ExchangeDeclare(
 		consumer.config.Exchange,     // name of the exchange
 		consumer.config.ExchangeType, // type
-		true,  // durable
-		false, // delete when complete
-		false, // internal
-		false, // noWait
-		nil,   // arguments
+		true,                         // durable
+		false,                        // delete when complete
+		false,                        // internal
+		false,                        // noWait
+		nil,                          // arguments
 	); err != nil {
 		err = consumer.logger.Errorf("exchange declare: %s", err).ToError()
 		return err
@@ -108,16 +112,27 @@ func (consumer *SimpleRabbitmqConsumer) Start(waitGroup ...*sync.WaitGroup) erro
 		consumer.queue,           // name of the queue
 		consumer.bindingKey,      // bindingKey
 		consumer.config.Exchange, // sourceExchange
-		false, // noWait
-		nil,   // arguments
+		false,                    // noWait
+		nil,                      // arguments
 	); err != nil {
 		err = consumer.logger.Errorf("queue bind: %s", err).ToError()
 		return err
 	}
 
 	consumer.logger.Infof("queue bound to exchange, starting consume (consumer tag '%s')", consumer.tag)
-	var deliveries <-chan amqp.Delivery
-	if deliveries, err = consumer.channel.Consume(
+	if err = consumer.consume(); err != nil {
+		return err
+	}
+
+	consumer.paused = false
+	consumer.state.setStarted(true)
+
+	return nil
+}
+
+// consume starts receiving the deliveries of the queue
+func (consumer *SimpleRabbitmqConsumer) consume() error {
+	deliveries, err := consumer.channel.Consume(
 		consumer.queue, // name
 		consumer.tag,   // consumerTag,
 		false,          // noAck
@@ -125,42 +140,116 @@ func (consumer *SimpleRabbitmqConsumer) Start(waitGroup ...*sync.WaitGroup) erro
 		false,          // noLocal
 		false,          // noWait
 		nil,            // arguments
-	); err != nil {
-		err = consumer.logger.Errorf("queue consume: %s", err).ToError()
-		return err
+	)
+	if err != nil {
+		return consumer.logger.Errorf("queue consume: %s", err).ToError()
 	}
 
+	consumer.done = make(chan error)
+	consumer.canceled = false
 	go consumer.handle(deliveries, consumer.done)
 
-	consumer.started = true
+	return nil
+}
+
+// Pause cancels the consumer, so no new deliveries are received, keeping the connection open
+func (consumer *SimpleRabbitmqConsumer) Pause() error {
+	consumer.state.lock()
+	defer consumer.state.unlock()
+
+	if !consumer.state.isStarted() {
+		return fmt.Errorf("rabbitmq consumer, not started")
+	}
+
+	if consumer.canceled {
+		return nil
+	}
+
+	if err := consumer.channel.Cancel(consumer.tag, false); err != nil {
+		return consumer.logger.Errorf("consumer cancel failed: %s", err).ToError()
+	}
+	consumer.canceled = true
+	consumer.paused = true
+
+	// wait for the deliveries in flight, where the consumer stays paused when they take longer
+	select {
+	case <-consumer.done:
+		return nil
+	case <-time.After(defaultRabbitmqPauseTimeout):
+		return fmt.Errorf("rabbitmq consumer, paused with deliveries still in flight after %s", defaultRabbitmqPauseTimeout)
+	}
+}
+
+// Resume ...
+func (consumer *SimpleRabbitmqConsumer) Resume() error {
+	consumer.state.lock()
+	defer consumer.state.unlock()
+
+	if !consumer.state.isStarted() {
+		return fmt.Errorf("rabbitmq consumer, not started")
+	}
+
+	if !consumer.paused {
+		return nil
+	}
+
+	if err := consumer.consume(); err != nil {
+		return err
+	}
+	consumer.paused = false
 
 	return nil
 }
 
+// Healthy ...
+func (consumer *SimpleRabbitmqConsumer) Healthy(ctx context.Context) error {
+	return checkRabbitmqConnection(consumer.closed)
+}
+
 func (consumer *SimpleRabbitmqConsumer) Started() bool {
-	return consumer.started
+	return consumer.state.isStarted()
 }
 
-func (consumer *SimpleRabbitmqConsumer) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
+// Drain cancels the consumer, so no new deliveries are received, and waits for the deliveries in flight
+func (consumer *SimpleRabbitmqConsumer) Drain(ctx context.Context) error {
+	consumer.state.lock()
+	defer consumer.state.unlock()
+
+	if !consumer.state.isStarted() || consumer.canceled {
+		return nil
+	}
+
+	// will close() the deliveries channel, after the server confirms
+	if err := consumer.channel.Cancel(consumer.tag, false); err != nil {
+		err = consumer.logger.Errorf("consumer cancel failed: %s", err).ToError()
+		return err
+	}
+	consumer.canceled = true
 
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
+	// wait for handle() to exit
+	select {
+	case <-consumer.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+}
 
-	defer wg.Done()
+func (consumer *SimpleRabbitmqConsumer) Stop(ctx context.Context) error {
+	consumer.state.lock()
+	defer consumer.state.unlock()
 
-	if !consumer.started {
+	if !consumer.state.isStarted() {
 		return nil
 	}
 
 	// will close() the deliveries channel
-	if err := consumer.channel.Cancel(consumer.tag, true); err != nil {
-		err = consumer.logger.Errorf("consumer cancel failed: %s", err).ToError()
-		return err
+	if !consumer.canceled {
+		if err := consumer.channel.Cancel(consumer.tag, true); err != nil {
+			err = consumer.logger.Errorf("consumer cancel failed: %s", err).ToError()
+			return err
+		}
+		consumer.canceled = true
 	}
 
 	if err := consumer.connection.Close(); err != nil {
@@ -170,23 +259,34 @@ func (consumer *SimpleRabbitmqConsumer) Stop(waitGroup ...*sync.WaitGroup) error
 
 	consumer.logger.Infof("AMQP shutdown OK")
 
-	consumer.started = false
+	consumer.state.setStarted(false)
 
 	// wait for handle() to exit
-	return <-consumer.done
+	select {
+	case err := <-consumer.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 
 }
 
 func (consumer *SimpleRabbitmqConsumer) handle(deliveries <-chan amqp.Delivery, done chan error) {
 	for delivery := range deliveries {
+		consumer.metrics.messages.Inc()
 		if err := consumer.handler(delivery); err != nil {
-			delivery.Ack(false)
+			consumer.metrics.errors.Inc()
+		}
+
+		if err := delivery.Ack(false); err != nil {
+			consumer.metrics.errors.Inc()
+			consumer.logger.Errorf("error acknowledging delivery [ %v ]: %s", delivery.DeliveryTag, err)
 		} else {
-			delivery.Ack(false)
+			consumer.metrics.acks.Inc()
 		}
 		consumer.logger.Infof("got %dB delivery: [%v] %s", len(delivery.Body), delivery.DeliveryTag, delivery.Body)
 	}
 
 	consumer.logger.Infof("handle: deliveries channel closed")
-	done <- nil
+	close(done)
 }
@@ -4,7 +4,7 @@ import (
 	"github.com/joaosoft/logger"
 	"time"
 
-	"sync"
+	"context"
 
 	"github.com/streadway/amqp"
 )
@@ -12,32 +12,27 @@ import (
 type SimpleRabbitmqProducer struct {
 	config     *RabbitmqConfig
 	connection *amqp.Connection
+	closed     chan *amqp.Error
 	channel    *amqp.Channel
 	tag        string
-	logger logger.ILogger
-	started    bool
+	metrics    *Metrics
+	logger     logger.ILogger
+	state      lifecycleState
 }
 
 func (manager *Manager) NewSimpleRabbitmqProducer(config *RabbitmqConfig) (*SimpleRabbitmqProducer, error) {
 	return &SimpleRabbitmqProducer{
-		config: config,
-		logger: manager.logger,
+		config:  config,
+		metrics: manager.metrics,
+		logger:  manager.logger,
 	}, nil
 }
 
-func (producer *SimpleRabbitmqProducer) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
+func (producer *SimpleRabbitmqProducer) Start(ctx context.Context) error {
+	producer.state.lock()
+	defer producer.state.unlock()
 
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if producer.started {
+	if producer.state.isStarted() {
 		return nil
 	}
 
@@ -47,6 +42,7 @@ func (producer *SimpleRabbitmqProducer) Start(waitGroup ...*sync.WaitGroup) erro
 		err = producer.logger.Errorf("dial: %s", err).ToError()
 		return err
 	}
+	producer.closed = producer.connection.NotifyClose(make(chan *amqp.Error, 1))
 
 	defer func(err error) {
 		if err != nil {
@@ -66,39 +62,35 @@ func (producer *SimpleRabbitmqProducer) Start(waitGroup ...*sync.WaitGroup) erro
 	if err = producer.channel.ExchangeDeclare(
 		producer.config.Exchange,     // name
 		producer.config.ExchangeType, // type
-		true,  // durable
-		false, // auto-deleted
-		false, // internal
-		false, // noWait
-		nil,   // arguments
+		true,                         // durable
+		false,                        // auto-deleted
+		false,                        // internal
+		false,                        // noWait
+		nil,                          // arguments
 	); err != nil {
 		err = producer.logger.Errorf("exchange declare: %s", err).ToError()
 		return err
 	}
 
-	producer.started = true
+	producer.state.setStarted(true)
 
 	return nil
 }
 
-func (producer *SimpleRabbitmqProducer) Started() bool {
-	producer.started = false
-	return producer.started
+// Healthy ...
+func (producer *SimpleRabbitmqProducer) Healthy(ctx context.Context) error {
+	return checkRabbitmqConnection(producer.closed)
 }
 
-func (producer *SimpleRabbitmqProducer) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
+func (producer *SimpleRabbitmqProducer) Started() bool {
+	return producer.state.isStarted()
+}
 
-	defer wg.Done()
+func (producer *SimpleRabbitmqProducer) Stop(ctx context.Context) error {
+	producer.state.lock()
+	defer producer.state.unlock()
 
-	if !producer.started {
+	if !producer.state.isStarted() {
 		return nil
 	}
 
@@ -114,7 +106,7 @@ func (producer *SimpleRabbitmqProducer) Stop(waitGroup ...*sync.WaitGroup) error
 	}
 
 	producer.logger.Infof("AMQP shutdown OK")
-	producer.started = false
+	producer.state.setStarted(false)
 
 	return nil
 }
@@ -130,6 +122,9 @@ func (producer *SimpleRabbitmqProducer) Publish(routingKey string, body []byte,
 		Priority:        0, // 0-9
 	}
 
+	metrics := newMessageMetrics(producer.metrics, "manager_rabbitmq_producer", Labels{"exchange": producer.config.Exchange, "routing_key": routingKey}, false, false)
+	metrics.messages.Inc()
+
 	producer.logger.Infof("declared exchange, publishing %dB body (%s)", len(body), body)
 	if err := producer.channel.Publish(
 		producer.config.Exchange, // publish to an exchange
@@ -138,6 +133,7 @@ func (producer *SimpleRabbitmqProducer) Publish(routingKey string, body []byte,
 		false,                    // immediate
 		msg,
 	); err != nil {
+		metrics.errors.Inc()
 		err = producer.logger.Errorf("exchange publish: %s", err).ToError()
 		return err
 	}
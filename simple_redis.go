@@ -7,41 +7,34 @@ import (
 
 	"strings"
 
-	"sync"
+	"context"
 
 	"github.com/alphazero/Go-Redis"
 )
 
 // SimpleRedis ...
 type SimpleRedis struct {
-	client  redis.Client
-	config  *RedisConfig
+	client redis.Client
+	config *RedisConfig
 	logger logger.ILogger
-	started bool
+	lease  redisLeaseConn
+	state  lifecycleState
 }
 
 // NewSimpleRedis ...
 func (manager *Manager) NewSimpleRedis(config *RedisConfig) IRedis {
 	return &SimpleRedis{
 		config: config,
-		logger:manager.logger,
+		logger: manager.logger,
 	}
 }
 
 // Start ...
-func (redis *SimpleRedis) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
+func (redis *SimpleRedis) Start(ctx context.Context) error {
+	redis.state.lock()
+	defer redis.state.unlock()
 
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if redis.started {
+	if redis.state.isStarted() {
 		return nil
 	}
 
@@ -50,40 +43,42 @@ func (redis *SimpleRedis) Start(waitGroup ...*sync.WaitGroup) error {
 		return err
 	} else {
 		redis.client = conn
-		redis.started = true
+		redis.state.setStarted(true)
 	}
 	return nil
 }
 
 // Stop ...
-func (redis *SimpleRedis) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
+func (redis *SimpleRedis) Stop(ctx context.Context) error {
+	redis.state.lock()
+	defer redis.state.unlock()
 
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if !redis.started {
+	if !redis.state.isStarted() {
 		return nil
 	}
 
 	if err := redis.client.Quit(); err != nil {
 		return err
 	}
+	redis.closeLease()
 
-	redis.started = false
+	redis.state.setStarted(false)
 
 	return nil
 }
 
+// Healthy ...
+func (redis *SimpleRedis) Healthy(ctx context.Context) error {
+	if !redis.state.isStarted() {
+		return fmt.Errorf("redis not started")
+	}
+
+	return runWithContext(ctx, redis.client.Ping)
+}
+
 // Started ...
 func (redis *SimpleRedis) Started() bool {
-	return redis.started
+	return redis.state.isStarted()
 }
 
 // Action ...
@@ -372,3 +367,43 @@ func (redis *SimpleRedis) Lastsave() (int64, error) {
 func (redis *SimpleRedis) Publish(channel string, message []byte) (int64, error) {
 	return redis.client.Publish(channel, message)
 }
+
+// Subscribe calls the handler with the messages of the channel on its own connection, until the context is done
+func (redis *SimpleRedis) Subscribe(ctx context.Context, channel string, handler RedisMessageHandler) error {
+	client, err := redis.config.ConnectPubSub()
+	if err != nil {
+		return err
+	}
+
+	if err = client.Subscribe(channel); err != nil {
+		client.Quit()
+		return err
+	}
+
+	messages := client.Messages(channel)
+	redis.logger.Infof("subscribed to the redis channel [ channel: %s ]", channel)
+
+	go func() {
+		defer func() {
+			if err := client.Unsubscribe(channel); err != nil {
+				redis.logger.Errorf("error unsubscribing the redis channel [ channel: %s ]: %s", channel, err)
+			}
+			client.Quit()
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					redis.logger.Errorf("the redis channel was closed [ channel: %s ]", channel)
+					return
+				}
+				handler(message)
+			}
+		}
+	}()
+
+	return nil
+}
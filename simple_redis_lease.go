@@ -0,0 +1,210 @@
+package manager
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRedisPort        = 6379
+	defaultRedisCallTimeout = 5 * time.Second
+
+	// the lease is only renewed or released by the replica that holds it, checking and changing it on the same script
+	renewLeaseScript   = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("pexpire", KEYS[1], ARGV[2]) else return 0 end`
+	releaseLeaseScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) else return 0 end`
+)
+
+// redisLeaseConn is the connection of the commands that the redis client doesn't have, as SET with NX and PX and EVAL
+type redisLeaseConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	mux    sync.Mutex
+}
+
+// AcquireLease sets the key to the value with the time to live when the key doesn't exist
+func (redis *SimpleRedis) AcquireLease(key string, value []byte, ttl time.Duration) (bool, error) {
+	reply, err := redis.call("SET", key, string(value), "NX", "PX", leaseMilliseconds(ttl))
+	if err != nil {
+		return false, err
+	}
+
+	// the key that already exists replies with a nil
+	return reply != nil, nil
+}
+
+// RenewLease sets the time to live of the key when it still has the value
+func (redis *SimpleRedis) RenewLease(key string, value []byte, ttl time.Duration) (bool, error) {
+	reply, err := redis.call("EVAL", renewLeaseScript, "1", key, string(value), leaseMilliseconds(ttl))
+	if err != nil {
+		return false, err
+	}
+
+	return reply == int64(1), nil
+}
+
+// ReleaseLease deletes the key when it still has the value
+func (redis *SimpleRedis) ReleaseLease(key string, value []byte) (bool, error) {
+	reply, err := redis.call("EVAL", releaseLeaseScript, "1", key, string(value))
+	if err != nil {
+		return false, err
+	}
+
+	return reply == int64(1), nil
+}
+
+// call runs the command on the lease connection, connecting it on the first call and after an error
+func (redis *SimpleRedis) call(args ...string) (interface{}, error) {
+	if !redis.state.isStarted() {
+		return nil, fmt.Errorf("redis not started")
+	}
+
+	lease := &redis.lease
+	lease.mux.Lock()
+	defer lease.mux.Unlock()
+
+	if lease.conn == nil {
+		if err := lease.connect(redis.config); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := lease.do(args...)
+	if _, ok := err.(redisReplyError); err != nil && !ok {
+		// the connection is left on an unknown state, so it's connected again on the next call
+		lease.close()
+	}
+
+	return reply, err
+}
+
+// closeLease closes the lease connection, when it was connected
+func (redis *SimpleRedis) closeLease() {
+	redis.lease.mux.Lock()
+	defer redis.lease.mux.Unlock()
+
+	redis.lease.close()
+}
+
+func (lease *redisLeaseConn) connect(config *RedisConfig) error {
+	port := config.Port
+	if port == 0 {
+		port = defaultRedisPort
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(config.Host, strconv.Itoa(port)), defaultRedisCallTimeout)
+	if err != nil {
+		return err
+	}
+	lease.conn, lease.reader = conn, bufio.NewReader(conn)
+
+	if config.Password != "" {
+		if _, err = lease.do("AUTH", config.Password); err != nil {
+			lease.close()
+			return err
+		}
+	}
+
+	if config.Database != 0 {
+		if _, err = lease.do("SELECT", strconv.Itoa(config.Database)); err != nil {
+			lease.close()
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (lease *redisLeaseConn) close() {
+	if lease.conn != nil {
+		lease.conn.Close()
+		lease.conn, lease.reader = nil, nil
+	}
+}
+
+// do writes the command as an array of bulk strings and reads its reply
+func (lease *redisLeaseConn) do(args ...string) (interface{}, error) {
+	if err := lease.conn.SetDeadline(time.Now().Add(defaultRedisCallTimeout)); err != nil {
+		return nil, err
+	}
+
+	command := []byte(fmt.Sprintf("*%d\r\n", len(args)))
+	for _, arg := range args {
+		command = append(command, fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg)...)
+	}
+
+	if _, err := lease.conn.Write(command); err != nil {
+		return nil, err
+	}
+
+	return lease.read()
+}
+
+// redisReplyError is an error replied by redis, after which the connection can still be used
+type redisReplyError string
+
+func (err redisReplyError) Error() string {
+	return string(err)
+}
+
+// read reads a reply, as a string, an int64, a []byte, a []interface{} or a nil
+func (lease *redisLeaseConn) read() (interface{}, error) {
+	line, err := lease.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("invalid redis reply %q", line)
+	}
+	kind, text := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return text, nil
+	case '-':
+		return nil, redisReplyError(text)
+	case ':':
+		return strconv.ParseInt(text, 10, 64)
+	case '$':
+		size, err := strconv.Atoi(text)
+		if err != nil || size < 0 {
+			return nil, err
+		}
+
+		data := make([]byte, size+2)
+		if _, err = io.ReadFull(lease.reader, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	case '*':
+		size, err := strconv.Atoi(text)
+		if err != nil || size < 0 {
+			return nil, err
+		}
+
+		items := make([]interface{}, size)
+		for i := range items {
+			if items[i], err = lease.read(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("invalid redis reply %q", line)
+	}
+}
+
+// leaseMilliseconds returns the time to live in milliseconds, as expected by PX and PEXPIRE
+func leaseMilliseconds(ttl time.Duration) string {
+	milliseconds := int64(ttl / time.Millisecond)
+	if milliseconds < 1 {
+		milliseconds = 1
+	}
+
+	return strconv.FormatInt(milliseconds, 10)
+}
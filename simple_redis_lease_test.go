@@ -0,0 +1,105 @@
+package manager
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSimpleRedisLease(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// the fake redis replies to each command by its name, and sends the commands it received
+	commands := make(chan []string, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		for {
+			var count int
+			if _, err := fmt.Fscanf(reader, "*%d\r\n", &count); err != nil {
+				return
+			}
+
+			args := make([]string, count)
+			for i := range args {
+				var size int
+				if _, err := fmt.Fscanf(reader, "$%d\r\n", &size); err != nil {
+					return
+				}
+				data := make([]byte, size+2)
+				if _, err := io.ReadFull(reader, data); err != nil {
+					return
+				}
+				args[i] = string(data[:size])
+			}
+			commands <- args
+
+			switch args[0] {
+			case "AUTH", "SELECT":
+				conn.Write([]byte("+OK\r\n"))
+			case "SET":
+				conn.Write([]byte("$-1\r\n"))
+			case "EVAL":
+				conn.Write([]byte(":1\r\n"))
+			default:
+				conn.Write([]byte("-ERR unknown command\r\n"))
+			}
+		}
+	}()
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	redis := newTestManager().NewSimpleRedis(NewRedisConfig("127.0.0.1", port, 2, "secret")).(*SimpleRedis)
+	redis.state.setStarted(true)
+	defer redis.closeLease()
+
+	acquired, err := redis.AcquireLease("lease", []byte("id"), 1500*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acquired {
+		t.Fatal("expected the lease not to be acquired on a nil reply")
+	}
+
+	renewed, err := redis.RenewLease("lease", []byte("id"), time.Second)
+	if err != nil || !renewed {
+		t.Fatalf("expected the lease to be renewed, got %t: %v", renewed, err)
+	}
+
+	expected := []string{
+		"AUTH secret",
+		"SELECT 2",
+		"SET lease id NX PX 1500",
+		"EVAL " + renewLeaseScript + " 1 lease id 1000",
+	}
+	for _, command := range expected {
+		select {
+		case args := <-commands:
+			if got := strings.Join(args, " "); got != command {
+				t.Fatalf("expected the command %q, got %q", command, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected the command %q", command)
+		}
+	}
+
+	// an error replied by redis keeps the connection
+	if _, err := redis.call("UNKNOWN"); err == nil {
+		t.Fatal("expected the error of the command")
+	}
+	if redis.lease.conn == nil {
+		t.Fatal("expected the connection to be kept after an error reply")
+	}
+}
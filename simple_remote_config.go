@@ -0,0 +1,478 @@
+package manager
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/joaosoft/logger"
+	"github.com/spf13/viper"
+)
+
+// RemoteConfigFormat is how the config is kept on redis
+type RemoteConfigFormat string
+
+const (
+	// RemoteConfigJSON keeps the config as a json document on a key
+	RemoteConfigJSON RemoteConfigFormat = "json"
+	// RemoteConfigHash keeps the config on a hash of the keys, as worklists.jobs.max_workers, and their json values,
+	// where the values that aren't json are taken as strings
+	RemoteConfigHash RemoteConfigFormat = "hash"
+)
+
+// defaultRemoteConfigPollInterval is the interval to check the config when the redis can't subscribe to the changes
+const defaultRemoteConfigPollInterval = 10 * time.Second
+
+// RemoteConfig is a config kept on redis by service and environment, shared by the replicas of the service,
+// that are notified of the changes through the redis pub/sub
+type RemoteConfig struct {
+	configAccessors
+	redis          IRedis
+	service        string
+	env            string
+	format         RemoteConfigFormat
+	fallbackFile   string
+	pollInterval   time.Duration
+	obj            interface{}
+	bytes          []byte
+	viper          *viper.Viper
+	changeHandlers []ConfigChangeHandler
+	keyHandlers    map[string][]ConfigKeyChangeHandler
+	cancel         context.CancelFunc
+	done           chan struct{}
+	logger         logger.ILogger
+	mux            sync.RWMutex
+	reloadMux      sync.Mutex
+}
+
+// NewRemoteConfig loads the config of the service from redis, or from the fallback file when redis isn't available
+func (manager *Manager) NewRemoteConfig(redis IRedis, service string, obj interface{}, options ...RemoteConfigOption) (*RemoteConfig, error) {
+	config := &RemoteConfig{
+		redis:       redis,
+		service:     service,
+		env:         GetEnv(),
+		format:      RemoteConfigJSON,
+		obj:         obj,
+		keyHandlers: make(map[string][]ConfigKeyChangeHandler),
+		logger:      manager.logger,
+	}
+	config.configAccessors = configAccessors{viper: config.getViper}
+	config.Reconfigure(options...)
+
+	if err := config.Reload(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Key is the redis key of the config, as manager:config:orders:production
+func (config *RemoteConfig) Key() string {
+	return fmt.Sprintf("manager:config:%s:%s", config.service, config.env)
+}
+
+// Channel is the redis channel where the changes of the config are published
+func (config *RemoteConfig) Channel() string {
+	return config.Key() + ":changes"
+}
+
+// Get ...
+func (config *RemoteConfig) Get(key string) interface{} {
+	return config.getViper().Get(key)
+}
+
+// GetString ...
+func (config *RemoteConfig) GetString(key string) string {
+	return config.getViper().GetString(key)
+}
+
+// GetBool ...
+func (config *RemoteConfig) GetBool(key string) bool {
+	return config.getViper().GetBool(key)
+}
+
+// GetInt ...
+func (config *RemoteConfig) GetInt(key string) int {
+	return config.getViper().GetInt(key)
+}
+
+// GetInt64 ...
+func (config *RemoteConfig) GetInt64(key string) int64 {
+	return config.getViper().GetInt64(key)
+}
+
+// GetFloat64 ...
+func (config *RemoteConfig) GetFloat64(key string) float64 {
+	return config.getViper().GetFloat64(key)
+}
+
+// GetTime ...
+func (config *RemoteConfig) GetTime(key string) time.Time {
+	return config.getViper().GetTime(key)
+}
+
+// GetDuration ...
+func (config *RemoteConfig) GetDuration(key string) time.Duration {
+	return config.getViper().GetDuration(key)
+}
+
+// GetStringSlice ...
+func (config *RemoteConfig) GetStringSlice(key string) []string {
+	return config.getViper().GetStringSlice(key)
+}
+
+// GetStringMap ...
+func (config *RemoteConfig) GetStringMap(key string) map[string]interface{} {
+	return config.getViper().GetStringMap(key)
+}
+
+// GetStringMapString ...
+func (config *RemoteConfig) GetStringMapString(key string) map[string]string {
+	return config.getViper().GetStringMapString(key)
+}
+
+// GetStringMapStringSlice ...
+func (config *RemoteConfig) GetStringMapStringSlice(key string) map[string][]string {
+	return config.getViper().GetStringMapStringSlice(key)
+}
+
+// GetObj returns the bound object, that is replaced by a new one on each change
+func (config *RemoteConfig) GetObj() interface{} {
+	config.mux.RLock()
+	defer config.mux.RUnlock()
+
+	return config.obj
+}
+
+// Set ...
+func (config *RemoteConfig) Set(obj interface{}) {
+	config.mux.Lock()
+	defer config.mux.Unlock()
+
+	config.obj = obj
+}
+
+// OnChange registers a handler called with the previous and the new object after each change
+func (config *RemoteConfig) OnChange(handler ConfigChangeHandler) {
+	config.mux.Lock()
+	defer config.mux.Unlock()
+
+	config.changeHandlers = append(config.changeHandlers, handler)
+}
+
+// OnKeyChange registers a handler called when the value of the key changes
+func (config *RemoteConfig) OnKeyChange(key string, handler ConfigKeyChangeHandler) {
+	config.mux.Lock()
+	defer config.mux.Unlock()
+
+	config.keyHandlers[key] = append(config.keyHandlers[key], handler)
+}
+
+// Reload reads the config from redis, or from the fallback file when redis isn't available,
+// replacing the bound object and calling the change handlers when it changed.
+// the previous object is kept when the config can't be read or when it is invalid
+func (config *RemoteConfig) Reload() error {
+	config.reloadMux.Lock()
+	defer config.reloadMux.Unlock()
+
+	data, err := config.fetch()
+	fromRedis := err == nil
+	if err != nil {
+		if config.fallbackFile == "" {
+			return err
+		}
+
+		config.logger.Warnf("the config isn't available on redis, loading the fallback file [ key: %s, file: %s ]: %s", config.Key(), config.fallbackFile, err)
+		if data, err = config.readFallback(); err != nil {
+			return err
+		}
+	}
+
+	config.mux.RLock()
+	unchanged, loaded := bytes.Equal(data, config.bytes), config.viper != nil
+	obj := config.obj
+	config.mux.RUnlock()
+	if unchanged && loaded {
+		return nil
+	}
+
+	// the first load fills the given object, and the next ones replace it
+	if loaded {
+		obj = newConfigObj(obj)
+	}
+
+	if obj != nil {
+		if err = json.Unmarshal(data, obj); err != nil {
+			return fmt.Errorf("invalid config %s: %s", config.Key(), err)
+		}
+
+		if err = ValidateConfig(obj); err != nil {
+			config.logger.Errorf("refusing the invalid config [ key: %s ]", config.Key())
+			return err
+		}
+	}
+
+	// the fallback file keeps the last config read from redis, for the next time redis isn't available
+	if fromRedis && config.fallbackFile != "" {
+		if err = writeConfigFile(config.fallbackFile, json.RawMessage(data)); err != nil {
+			config.logger.Errorf("error writing the fallback file of the config [ key: %s, file: %s ]: %s", config.Key(), config.fallbackFile, err)
+		}
+	}
+
+	viper := loadViper(data)
+
+	config.mux.Lock()
+	oldObj, oldViper := config.obj, config.viper
+	config.obj, config.bytes, config.viper = obj, data, viper
+	changeHandlers, keyHandlers := copyConfigHandlers(config.changeHandlers, config.keyHandlers)
+	config.mux.Unlock()
+
+	if loaded {
+		notifyConfigChange(config.Key(), oldObj, obj, oldViper, viper, changeHandlers, keyHandlers, config.logger)
+	}
+
+	return nil
+}
+
+// Save writes the object to redis when it is valid, notifying the other replicas of the change
+func (config *RemoteConfig) Save() error {
+	if !config.redis.Started() {
+		return fmt.Errorf("the redis of the config %s isn't started", config.Key())
+	}
+
+	obj := config.GetObj()
+	if err := ValidateConfig(obj); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	switch config.format {
+	case RemoteConfigHash:
+		values, err := toConfigMap(obj)
+		if err != nil {
+			return err
+		}
+
+		fields := make(map[string]interface{})
+		flattenConfig(values, "", fields)
+
+		if err = config.saveHash(fields); err != nil {
+			return err
+		}
+	default:
+		if err = config.redis.Set(config.Key(), data); err != nil {
+			return err
+		}
+	}
+
+	if _, err = config.redis.Publish(config.Channel(), []byte(config.Key())); err != nil {
+		config.logger.Errorf("error publishing the change of the config [ key: %s ]: %s", config.Key(), err)
+	}
+
+	config.logger.Infof("config saved [ key: %s ]", config.Key())
+
+	return config.Reload()
+}
+
+// saveHash writes the fields on a temporary hash that replaces the hash of the config at once when it's complete,
+// so the replicas never read a partial config and the keys removed from the object don't stay on it
+func (config *RemoteConfig) saveHash(fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		_, err := config.redis.Del(config.Key())
+		return err
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := io.ReadFull(rand.Reader, suffix); err != nil {
+		return err
+	}
+	tmpKey := fmt.Sprintf("%s:saving:%s", config.Key(), hex.EncodeToString(suffix))
+
+	for _, field := range sortedKeys(fields) {
+		value, err := json.Marshal(fields[field])
+		if err == nil {
+			err = config.redis.Hset(tmpKey, field, value)
+		}
+
+		if err != nil {
+			config.redis.Del(tmpKey)
+			return err
+		}
+	}
+
+	if err := config.redis.Rename(tmpKey, config.Key()); err != nil {
+		config.redis.Del(tmpKey)
+		return err
+	}
+
+	return nil
+}
+
+// Watch reloads the config when it changes, through the redis pub/sub or by polling it
+// when the redis can't subscribe to the changes
+func (config *RemoteConfig) Watch() error {
+	config.mux.Lock()
+	defer config.mux.Unlock()
+
+	if config.cancel != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reload := func() {
+		if err := config.Reload(); err != nil {
+			config.logger.Errorf("error reloading the config [ key: %s ]: %s", config.Key(), err)
+		}
+	}
+
+	if subscriber, ok := config.redis.(IRedisSubscriber); ok && config.pollInterval <= 0 {
+		err := subscriber.Subscribe(ctx, config.Channel(), func(message []byte) { reload() })
+		if err == nil {
+			config.cancel, config.done = cancel, nil
+			return nil
+		}
+
+		config.logger.Warnf("error subscribing to the changes of the config, polling it [ key: %s ]: %s", config.Key(), err)
+	}
+
+	interval := config.pollInterval
+	if interval <= 0 {
+		interval = defaultRemoteConfigPollInterval
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				reload()
+			}
+		}
+	}()
+
+	config.cancel, config.done = cancel, done
+
+	return nil
+}
+
+// Unwatch stops watching the changes
+func (config *RemoteConfig) Unwatch() {
+	config.mux.Lock()
+	cancel, done := config.cancel, config.done
+	config.cancel, config.done = nil, nil
+	config.mux.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	if done != nil {
+		<-done
+	}
+}
+
+// fetch reads the config from redis as json
+func (config *RemoteConfig) fetch() ([]byte, error) {
+	if !config.redis.Started() {
+		return nil, fmt.Errorf("the redis isn't started")
+	}
+
+	switch config.format {
+	case RemoteConfigHash:
+		pairs, err := config.redis.Hgetall(config.Key())
+		if err != nil {
+			return nil, err
+		}
+
+		if len(pairs) == 0 {
+			return nil, fmt.Errorf("the config key %s doesn't exist", config.Key())
+		}
+
+		// the text of the fields is converted to the type of the fields of the object
+		target := reflect.TypeOf(config.GetObj())
+
+		values := make(map[string]interface{})
+		for i := 0; i+1 < len(pairs); i += 2 {
+			data := pairs[i+1]
+			setConfigPath(values, strings.Split(string(pairs[i]), "."), target, func(previous interface{}, target reflect.Type) interface{} {
+				return parseRemoteConfigValue(data, target)
+			})
+		}
+
+		return json.Marshal(values)
+	default:
+		data, err := config.redis.Get(config.Key())
+		if err != nil {
+			return nil, err
+		}
+
+		if data == nil {
+			return nil, fmt.Errorf("the config key %s doesn't exist", config.Key())
+		}
+
+		return data, nil
+	}
+}
+
+// parseRemoteConfigValue reads a field of the hash, that is saved as json but can be set by hand as text,
+// keeping the text when it isn't json or when the field of the object is a string
+func parseRemoteConfigValue(data []byte, target reflect.Type) interface{} {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return parseConfigValue(nil, string(data), target)
+	}
+
+	if _, isString := value.(string); !isString && target != nil && target.Kind() == reflect.String {
+		return string(data)
+	}
+
+	return value
+}
+
+// readFallback reads the fallback file as json
+func (config *RemoteConfig) readFallback() ([]byte, error) {
+	data, err := ReadFile(config.fallbackFile, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the fallback file %s of the config %s: %s", config.fallbackFile, config.Key(), err)
+	}
+
+	return configJSON(config.fallbackFile, data)
+}
+
+func (config *RemoteConfig) getViper() *viper.Viper {
+	config.mux.RLock()
+	defer config.mux.RUnlock()
+
+	return config.viper
+}
+
+// flattenConfig sets the leaves of the values on the fields by their keys, as worklists.jobs.max_workers
+func flattenConfig(values map[string]interface{}, prefix string, fields map[string]interface{}) {
+	for key := range values {
+		if nested, ok := values[key].(map[string]interface{}); ok && len(nested) > 0 {
+			flattenConfig(nested, prefix+key+".", fields)
+			continue
+		}
+
+		fields[prefix+key] = values[key]
+	}
+}
@@ -0,0 +1,44 @@
+package manager
+
+import (
+	"time"
+)
+
+// RemoteConfigOption ...
+type RemoteConfigOption func(config *RemoteConfig)
+
+// Reconfigure ...
+func (config *RemoteConfig) Reconfigure(options ...RemoteConfigOption) {
+	for _, option := range options {
+		option(config)
+	}
+}
+
+// WithRemoteConfigFormat sets how the config is kept on redis, as a json document or a hash
+func WithRemoteConfigFormat(format RemoteConfigFormat) RemoteConfigOption {
+	return func(config *RemoteConfig) {
+		config.format = format
+	}
+}
+
+// WithRemoteConfigEnv sets the environment of the config, that is the one of GetEnv by default
+func WithRemoteConfigEnv(env string) RemoteConfigOption {
+	return func(config *RemoteConfig) {
+		config.env = env
+	}
+}
+
+// WithRemoteConfigFallback sets the json or yaml file loaded when redis isn't available,
+// that is updated with the config read from redis
+func WithRemoteConfigFallback(file string) RemoteConfigOption {
+	return func(config *RemoteConfig) {
+		config.fallbackFile = file
+	}
+}
+
+// WithRemoteConfigPolling makes the watcher read the config on each interval instead of subscribing to its changes
+func WithRemoteConfigPolling(interval time.Duration) RemoteConfigOption {
+	return func(config *RemoteConfig) {
+		config.pollInterval = interval
+	}
+}
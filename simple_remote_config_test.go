@@ -0,0 +1,112 @@
+package manager
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRemoteConfig(t *testing.T) {
+	type serviceConfig struct {
+		MaxWorkers int               `json:"max_workers" validate:"min=1"`
+		Features   map[string]bool   `json:"features"`
+		Labels     map[string]string `json:"labels"`
+	}
+
+	for _, format := range []RemoteConfigFormat{RemoteConfigJSON, RemoteConfigHash} {
+		t.Run(string(format), func(t *testing.T) {
+			redis := newMemoryRedis()
+			fallback := filepath.Join(t.TempDir(), "orders.json")
+			if err := ioutil.WriteFile(fallback, []byte(`{"max_workers": 1, "labels": {"team": "fallback"}}`), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			options := []RemoteConfigOption{WithRemoteConfigFormat(format), WithRemoteConfigEnv("test"), WithRemoteConfigFallback(fallback)}
+
+			// the replica starts without the config on redis, from the fallback file
+			var obj serviceConfig
+			replica, err := newTestManager().NewRemoteConfig(redis, "orders", &obj, options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if obj.MaxWorkers != 1 || replica.GetString("labels.team") != "fallback" || replica.Key() != "manager:config:orders:test" {
+				t.Fatalf("expected the fallback config, got %+v", obj)
+			}
+
+			changes := make(chan int, 1)
+			replica.OnKeyChange("max_workers", func(key string, oldValue, newValue interface{}) {
+				changes <- replica.GetInt(key)
+			})
+			if err = replica.Watch(); err != nil {
+				t.Fatal(err)
+			}
+			defer replica.Unwatch()
+
+			admin, err := newTestManager().NewRemoteConfig(redis, "orders", &serviceConfig{}, options...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			updated := admin.GetObj().(*serviceConfig)
+			updated.MaxWorkers = 4
+			updated.Features = map[string]bool{"new_checkout": true}
+			updated.Labels = map[string]string{"team": "orders"}
+			if err = admin.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case maxWorkers := <-changes:
+				if maxWorkers != 4 || !replica.GetBool("features.new_checkout") || replica.GetString("labels.team") != "orders" {
+					t.Fatalf("unexpected config after the change %+v", replica.GetObj())
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("expected the replica to be notified of the change")
+			}
+
+			// the fallback file keeps the last config of redis
+			data, err := ioutil.ReadFile(fallback)
+			if err != nil || !strings.Contains(string(data), `"new_checkout": true`) {
+				t.Fatalf("expected the fallback file to be updated, got %s %v", data, err)
+			}
+
+			// the saved config was reloaded into a new object
+			updated = admin.GetObj().(*serviceConfig)
+			updated.MaxWorkers = 0
+			if err = admin.Save(); err == nil {
+				t.Fatal("expected the invalid config not to be saved")
+			}
+
+			// the fields set by hand on the hash keep the type of the fields of the object
+			if format == RemoteConfigHash {
+				// the hash is written on a temporary key that replaces the one of the config
+				redis.mux.Lock()
+				hashes := len(redis.hashes)
+				redis.mux.Unlock()
+				if hashes != 1 {
+					t.Fatalf("expected only the hash of the config to be kept, got %d hashes", hashes)
+				}
+
+				if err = redis.Hset(admin.Key(), "labels.build", []byte("42")); err != nil {
+					t.Fatal(err)
+				}
+				if err = admin.Reload(); err != nil || admin.GetObj().(*serviceConfig).Labels["build"] != "42" {
+					t.Fatalf("expected the text of the field set by hand, got %+v %v", admin.GetObj(), err)
+				}
+			}
+
+			redis.mux.Lock()
+			redis.stopped = true
+			redis.mux.Unlock()
+
+			if err = admin.Save(); err == nil {
+				t.Fatal("expected an error saving without redis")
+			}
+			if err = replica.Reload(); err != nil || replica.GetInt("max_workers") != 4 {
+				t.Fatalf("expected the fallback file to be used without redis, got %d %v", replica.GetInt("max_workers"), err)
+			}
+		})
+	}
+}
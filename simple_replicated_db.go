@@ -0,0 +1,300 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/joaosoft/logger"
+)
+
+// ReplicaPolicy defines how the reads are balanced between the healthy replicas
+type ReplicaPolicy string
+
+const (
+	// ReplicaRoundRobin takes the replicas in turn
+	ReplicaRoundRobin ReplicaPolicy = "round-robin"
+	// ReplicaLeastConnections takes the replica with the fewest connections in use
+	ReplicaLeastConnections ReplicaPolicy = "least-connections"
+)
+
+const (
+	defaultReplicaHealthInterval = 5 * time.Second
+	defaultReplicaHealthTimeout  = 2 * time.Second
+	defaultReplicaMaxFailures    = 2
+)
+
+// ReplicatedDB is a primary database for the writes and its replicas for the reads, where the replicas
+// that fail the health checks are ejected until they answer again, and the reads fall back to the primary
+// when there isn't a healthy replica
+type ReplicatedDB struct {
+	primary        *SimpleDB
+	replicas       []*dbReplica
+	policy         ReplicaPolicy
+	healthInterval time.Duration
+	maxFailures    int
+	next           uint64
+	logger         logger.ILogger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	mux    sync.RWMutex
+	state  lifecycleState
+}
+
+// dbReplica is a replica with the state of its health checks
+type dbReplica struct {
+	index    int
+	db       *SimpleDB
+	healthy  bool
+	failures int
+}
+
+// NewReplicatedDB ...
+func (manager *Manager) NewReplicatedDB(primary *DBConfig, replicas []*DBConfig, options ...ReplicatedDBOption) IReplicatedDB {
+	db := &ReplicatedDB{
+		primary:        manager.NewSimpleDB(primary).(*SimpleDB),
+		policy:         ReplicaRoundRobin,
+		healthInterval: defaultReplicaHealthInterval,
+		maxFailures:    defaultReplicaMaxFailures,
+		logger:         manager.logger,
+	}
+
+	for i, config := range replicas {
+		db.replicas = append(db.replicas, &dbReplica{index: i, db: manager.NewSimpleDB(config).(*SimpleDB)})
+	}
+	db.Reconfigure(options...)
+
+	return db
+}
+
+// Get returns the primary, as the Writer
+func (db *ReplicatedDB) Get() *sql.DB {
+	return db.Writer()
+}
+
+// Writer returns the primary
+func (db *ReplicatedDB) Writer() *sql.DB {
+	return db.primary.Get()
+}
+
+// Reader returns a healthy replica by the replica policy, or the primary when there isn't one
+func (db *ReplicatedDB) Reader() *sql.DB {
+	db.mux.RLock()
+	healthy := make([]*dbReplica, 0, len(db.replicas))
+	for _, replica := range db.replicas {
+		if replica.healthy {
+			healthy = append(healthy, replica)
+		}
+	}
+	db.mux.RUnlock()
+
+	if len(healthy) == 0 {
+		return db.primary.Get()
+	}
+
+	switch db.policy {
+	case ReplicaLeastConnections:
+		selected := healthy[0]
+		inUse := selected.db.Stats().InUse
+		for _, replica := range healthy[1:] {
+			if stats := replica.db.Stats(); stats.InUse < inUse {
+				selected, inUse = replica, stats.InUse
+			}
+		}
+		return selected.db.Get()
+	default:
+		next := atomic.AddUint64(&db.next, 1) - 1
+		return healthy[next%uint64(len(healthy))].db.Get()
+	}
+}
+
+// HealthyReplicas returns the number of replicas that are taking reads
+func (db *ReplicatedDB) HealthyReplicas() int {
+	db.mux.RLock()
+	defer db.mux.RUnlock()
+
+	var count int
+	for _, replica := range db.replicas {
+		if replica.healthy {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Start starts the primary, failing when it isn't reachable, and the replicas,
+// where the ones that aren't reachable are left out of the reads until they answer the health checks
+func (db *ReplicatedDB) Start(ctx context.Context) error {
+	db.state.lock()
+	defer db.state.unlock()
+
+	if db.state.isStarted() {
+		return nil
+	}
+
+	if err := db.primary.Start(ctx); err != nil {
+		return err
+	}
+
+	for _, replica := range db.replicas {
+		err := replica.db.Start(ctx)
+		if err != nil {
+			db.logger.Warnf("error starting the database replica, leaving it out of the reads [ replica: %d ]: %s", replica.index, err)
+		}
+
+		db.mux.Lock()
+		replica.healthy, replica.failures = err == nil, 0
+		db.mux.Unlock()
+	}
+
+	// the health checks run until the database is stopped, not until the start context is done
+	checkCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	db.mux.Lock()
+	db.cancel, db.done = cancel, done
+	db.mux.Unlock()
+
+	db.state.setStarted(true)
+	go db.checkReplicas(checkCtx, done)
+
+	return nil
+}
+
+// Stop stops the health checks, the replicas and the primary.
+// the database is stopped even when a replica or the primary fails to stop, as its health checks are stopped,
+// and it can be started again
+func (db *ReplicatedDB) Stop(ctx context.Context) error {
+	db.state.lock()
+	defer db.state.unlock()
+
+	if !db.state.isStarted() {
+		return nil
+	}
+
+	db.mux.Lock()
+	cancel, done := db.cancel, db.done
+	db.mux.Unlock()
+
+	// the health checks are only forgotten when they finished, so a stop that timed out can be retried
+	if cancel != nil {
+		cancel()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return fmt.Errorf("timeout waiting for the health checks of the replicas to stop: %s", ctx.Err())
+		}
+
+		db.mux.Lock()
+		db.cancel, db.done = nil, nil
+		db.mux.Unlock()
+	}
+
+	var errs []error
+	for _, replica := range db.replicas {
+		if err := replica.db.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("replica %d: %s", replica.index, err))
+		}
+
+		db.mux.Lock()
+		replica.healthy = false
+		db.mux.Unlock()
+	}
+
+	if err := db.primary.Stop(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("primary: %s", err))
+	}
+
+	db.state.setStarted(false)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("error stopping the replicated database: %v", errs)
+	}
+
+	return nil
+}
+
+// Healthy checks the primary, as the reads fall back to it when the replicas are down
+func (db *ReplicatedDB) Healthy(ctx context.Context) error {
+	return db.primary.Healthy(ctx)
+}
+
+// Started ...
+func (db *ReplicatedDB) Started() bool {
+	return db.state.isStarted()
+}
+
+// Stats returns the statistics of the connection pool of the primary
+func (db *ReplicatedDB) Stats() sql.DBStats {
+	return db.primary.Stats()
+}
+
+// ReplicaStats returns the statistics of the connection pools of the replicas, by their order
+func (db *ReplicatedDB) ReplicaStats() []sql.DBStats {
+	stats := make([]sql.DBStats, len(db.replicas))
+	for i, replica := range db.replicas {
+		stats[i] = replica.db.Stats()
+	}
+
+	return stats
+}
+
+// Ping pings the primary
+func (db *ReplicatedDB) Ping(ctx context.Context) error {
+	return db.primary.Ping(ctx)
+}
+
+// checkReplicas checks the health of the replicas on each interval
+func (db *ReplicatedDB) checkReplicas(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(db.healthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, replica := range db.replicas {
+				db.checkReplica(ctx, replica)
+			}
+		}
+	}
+}
+
+// checkReplica ejects the replica after the max consecutive failures of the health checks,
+// and takes it back on the first health check that succeeds, starting it when it wasn't reachable on start
+func (db *ReplicatedDB) checkReplica(ctx context.Context, replica *dbReplica) {
+	checkCtx, cancel := context.WithTimeout(ctx, defaultReplicaHealthTimeout)
+	defer cancel()
+
+	var err error
+	if replica.db.Started() {
+		err = replica.db.Ping(checkCtx)
+	} else {
+		err = replica.db.Start(checkCtx)
+	}
+
+	db.mux.Lock()
+	defer db.mux.Unlock()
+
+	if err == nil {
+		if !replica.healthy {
+			db.logger.Infof("database replica back to the reads [ replica: %d ]", replica.index)
+		}
+		replica.healthy, replica.failures = true, 0
+		return
+	}
+
+	replica.failures++
+	if replica.healthy && replica.failures >= db.maxFailures {
+		replica.healthy = false
+		db.logger.Warnf("database replica ejected from the reads [ replica: %d, failures: %d ]: %s", replica.index, replica.failures, err)
+	}
+}
@@ -0,0 +1,31 @@
+package manager
+
+import (
+	"time"
+)
+
+// ReplicatedDBOption ...
+type ReplicatedDBOption func(db *ReplicatedDB)
+
+// Reconfigure ...
+func (db *ReplicatedDB) Reconfigure(options ...ReplicatedDBOption) {
+	for _, option := range options {
+		option(db)
+	}
+}
+
+// WithReplicaPolicy ...
+func WithReplicaPolicy(policy ReplicaPolicy) ReplicatedDBOption {
+	return func(db *ReplicatedDB) {
+		db.policy = policy
+	}
+}
+
+// WithReplicaHealthCheck sets the interval of the health checks of the replicas,
+// and the consecutive failures that eject a replica from the reads
+func WithReplicaHealthCheck(interval time.Duration, maxFailures int) ReplicatedDBOption {
+	return func(db *ReplicatedDB) {
+		db.healthInterval = interval
+		db.maxFailures = maxFailures
+	}
+}
@@ -0,0 +1,118 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestReplicatedDBStop(t *testing.T) {
+	manager := newTestManager()
+
+	config := func(name string) *DBConfig {
+		return &DBConfig{Driver: "manager-test", DataSource: name, PingBackoff: "1ms"}
+	}
+
+	db := manager.NewReplicatedDB(config("primary-stop"), []*DBConfig{config("replica-stop")},
+		WithReplicaHealthCheck(time.Millisecond, 1)).(*ReplicatedDB)
+	if err := db.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// a stop that times out, or not, can always be called again
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = db.Stop(canceled)
+
+	if err := db.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if db.Started() || db.Writer().Ping() == nil {
+		t.Fatal("expected the database to be stopped")
+	}
+
+	if err := db.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if db.HealthyReplicas() != 1 || db.Reader() == db.Writer() {
+		t.Fatal("expected the replica back on the reads after starting again")
+	}
+
+	if err := db.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReplicatedDB(t *testing.T) {
+	manager := newTestManager()
+
+	config := func(name string) *DBConfig {
+		return &DBConfig{Driver: "manager-test", DataSource: name, PingBackoff: "1ms"}
+	}
+
+	waitReplicas := func(db IReplicatedDB, healthy int) {
+		deadline := time.Now().Add(time.Second)
+		for db.(*ReplicatedDB).HealthyReplicas() != healthy {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d healthy replicas, got %d", healthy, db.(*ReplicatedDB).HealthyReplicas())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	testDB.fail("replica-1", -1)
+	db := manager.NewReplicatedDB(config("primary"), []*DBConfig{config("replica-0"), config("replica-1")},
+		WithReplicaHealthCheck(5*time.Millisecond, 2))
+	if err := manager.AddDB("replicated", db); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.(*ReplicatedDB).Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer db.(*ReplicatedDB).Stop(context.Background())
+
+	// the replica that isn't reachable on start is left out of the reads
+	if db.(*ReplicatedDB).HealthyReplicas() != 1 || db.Reader() == db.Writer() || db.Reader() != db.Reader() || manager.GetDB("replicated").Get() != db.Writer() {
+		t.Fatal("expected the reads on the reachable replica and the writes on the primary")
+	}
+
+	testDB.fail("replica-1", 0)
+	waitReplicas(db, 2)
+
+	readers := map[*sql.DB]bool{db.Reader(): true, db.Reader(): true}
+	if len(readers) != 2 || readers[db.Writer()] {
+		t.Fatal("expected the reads to be balanced between the replicas")
+	}
+
+	testDB.fail("replica-0", -1)
+	testDB.fail("replica-1", -1)
+	waitReplicas(db, 0)
+
+	if db.Reader() != db.Writer() {
+		t.Fatal("expected the reads to fall back to the primary when the replicas are down")
+	}
+
+	testDB.fail("replica-0", 0)
+	testDB.fail("replica-1", 0)
+	waitReplicas(db, 2)
+
+	leastConnections := manager.NewReplicatedDB(config("primary-lc"), []*DBConfig{config("replica-lc-0"), config("replica-lc-1")},
+		WithReplicaPolicy(ReplicaLeastConnections))
+	if err := leastConnections.(*ReplicatedDB).Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer leastConnections.(*ReplicatedDB).Stop(context.Background())
+
+	busy := leastConnections.Reader()
+	conn, err := busy.Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if reader := leastConnections.Reader(); reader == busy || reader == leastConnections.Writer() {
+		t.Fatal("expected the reads on the replica with the fewest connections in use")
+	}
+}
@@ -0,0 +1,270 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/joaosoft/logger"
+	"github.com/robfig/cron"
+)
+
+// OverlapPolicy defines what happens when a scheduled run is due while the previous one is still running
+type OverlapPolicy string
+
+const (
+	// OverlapSkip skips the runs that are due while the previous one is running
+	OverlapSkip OverlapPolicy = "skip"
+	// OverlapQueue runs the runs that are due while the previous one is running after it, one at a time
+	OverlapQueue OverlapPolicy = "queue"
+)
+
+// Schedule returns the next run time after the given time
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// scheduleParser accepts the cron expressions with an optional seconds field, the CRON_TZ or TZ prefixes
+// and the descriptors, like @hourly and @every 5m
+var scheduleParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// ParseSchedule parses a cron expression, like "*/30 * * * * *" or "CRON_TZ=Europe/Lisbon 0 8 * * MON-FRI"
+func ParseSchedule(spec string) (Schedule, error) {
+	schedule, err := scheduleParser.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule [ %s ]: %s", spec, err)
+	}
+
+	return schedule, nil
+}
+
+// Every returns a schedule with a fixed interval between the runs
+func Every(interval time.Duration) Schedule {
+	return intervalSchedule(interval)
+}
+
+type intervalSchedule time.Duration
+
+// Next ...
+func (interval intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(interval))
+}
+
+// ScheduledProcess runs a function on a schedule, until it's stopped
+type ScheduledProcess struct {
+	name       string
+	schedule   Schedule
+	function   func(ctx context.Context) error
+	jitter     time.Duration
+	overlap    OverlapPolicy
+	runOnStart bool
+	location   *time.Location
+	logger     logger.ILogger
+
+	cancel    context.CancelFunc
+	done      chan struct{}
+	runs      sync.WaitGroup
+	running   bool
+	pending   int
+	lastRun   time.Time
+	lastError error
+	nextRun   time.Time
+	mux       sync.Mutex
+	state     lifecycleState
+}
+
+// NewScheduledProcess ...
+func (manager *Manager) NewScheduledProcess(name string, schedule Schedule, function func(ctx context.Context) error, options ...ScheduledProcessOption) *ScheduledProcess {
+	process := &ScheduledProcess{
+		name:     name,
+		schedule: schedule,
+		function: function,
+		overlap:  OverlapSkip,
+		location: time.Local,
+		logger:   manager.logger,
+	}
+	process.Reconfigure(options...)
+
+	return process
+}
+
+// Start ...
+func (process *ScheduledProcess) Start(ctx context.Context) error {
+	process.state.lock()
+	defer process.state.unlock()
+
+	if process.state.isStarted() {
+		return nil
+	}
+
+	// the runs are scheduled until the process is stopped, not until the start context is done
+	runCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	process.mux.Lock()
+	process.cancel = cancel
+	process.done = done
+	process.pending = 0
+	process.mux.Unlock()
+
+	if process.runOnStart {
+		process.trigger(runCtx)
+	}
+
+	go process.scheduler(runCtx, done)
+	process.state.setStarted(true)
+
+	return nil
+}
+
+// Stop stops scheduling runs and waits for the run in progress, dropping the queued ones
+func (process *ScheduledProcess) Stop(ctx context.Context) error {
+	process.state.lock()
+	defer process.state.unlock()
+
+	if !process.state.isStarted() {
+		return nil
+	}
+
+	process.mux.Lock()
+	cancel, done := process.cancel, process.done
+	process.mux.Unlock()
+
+	cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		<-done
+		process.runs.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		return fmt.Errorf("timeout waiting for the scheduled process %s to stop: %s", process.name, ctx.Err())
+	}
+
+	process.mux.Lock()
+	process.nextRun = time.Time{}
+	process.mux.Unlock()
+
+	process.state.setStarted(false)
+
+	return nil
+}
+
+// Started ...
+func (process *ScheduledProcess) Started() bool {
+	return process.state.isStarted()
+}
+
+// LastRun returns the start time of the last run, or zero when it didn't run yet
+func (process *ScheduledProcess) LastRun() time.Time {
+	process.mux.Lock()
+	defer process.mux.Unlock()
+
+	return process.lastRun
+}
+
+// LastError returns the error of the last finished run
+func (process *ScheduledProcess) LastError() error {
+	process.mux.Lock()
+	defer process.mux.Unlock()
+
+	return process.lastError
+}
+
+// NextRun returns the time of the next run, or zero when the process isn't started
+func (process *ScheduledProcess) NextRun() time.Time {
+	process.mux.Lock()
+	defer process.mux.Unlock()
+
+	return process.nextRun
+}
+
+// scheduler triggers the runs on the schedule until the context is done
+func (process *ScheduledProcess) scheduler(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	for {
+		next := process.schedule.Next(time.Now().In(process.location))
+		if next.IsZero() {
+			process.logger.Infof("no more runs scheduled [ name: %s ]", process.name)
+			return
+		}
+
+		if process.jitter > 0 {
+			next = next.Add(time.Duration(rand.Int63n(int64(process.jitter))))
+		}
+
+		process.mux.Lock()
+		process.nextRun = next
+		process.mux.Unlock()
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		process.trigger(ctx)
+	}
+}
+
+// trigger starts a run, or skips or queues it by the overlap policy when there is a run in progress
+func (process *ScheduledProcess) trigger(ctx context.Context) {
+	process.mux.Lock()
+	defer process.mux.Unlock()
+
+	if process.running {
+		if process.overlap == OverlapQueue {
+			process.pending++
+			process.logger.Infof("queued run of scheduled process [ name: %s, pending: %d ]", process.name, process.pending)
+		} else {
+			process.logger.Infof("skipped run of scheduled process still running [ name: %s ]", process.name)
+		}
+		return
+	}
+
+	process.running = true
+	process.runs.Add(1)
+	go process.run(ctx)
+}
+
+// run executes the function, and the queued runs after it
+func (process *ScheduledProcess) run(ctx context.Context) {
+	defer process.runs.Done()
+
+	for {
+		start := time.Now()
+
+		process.mux.Lock()
+		process.lastRun = start
+		process.mux.Unlock()
+
+		err := runRecovered(ctx, process.function)
+		if err != nil {
+			process.logger.Errorf("error on scheduled process [ name: %s ]: %s", process.name, err)
+		} else {
+			process.logger.Debugf("scheduled process finished [ name: %s, elapsed: %s ]", process.name, time.Since(start))
+		}
+
+		process.mux.Lock()
+		process.lastError = err
+
+		if process.pending == 0 || ctx.Err() != nil {
+			process.pending = 0
+			process.running = false
+			process.mux.Unlock()
+			return
+		}
+
+		process.pending--
+		process.mux.Unlock()
+	}
+}
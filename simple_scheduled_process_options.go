@@ -0,0 +1,43 @@
+package manager
+
+import (
+	"time"
+)
+
+// ScheduledProcessOption ...
+type ScheduledProcessOption func(process *ScheduledProcess)
+
+// Reconfigure ...
+func (process *ScheduledProcess) Reconfigure(options ...ScheduledProcessOption) {
+	for _, option := range options {
+		option(process)
+	}
+}
+
+// WithScheduleJitter delays each run by a random duration of up to the given one, where zero disables it
+func WithScheduleJitter(jitter time.Duration) ScheduledProcessOption {
+	return func(process *ScheduledProcess) {
+		process.jitter = jitter
+	}
+}
+
+// WithOverlapPolicy ...
+func WithOverlapPolicy(overlap OverlapPolicy) ScheduledProcessOption {
+	return func(process *ScheduledProcess) {
+		process.overlap = overlap
+	}
+}
+
+// WithRunOnStart runs the function when the process starts, besides on its schedule
+func WithRunOnStart(runOnStart bool) ScheduledProcessOption {
+	return func(process *ScheduledProcess) {
+		process.runOnStart = runOnStart
+	}
+}
+
+// WithLocation sets the time zone of the cron expressions without a CRON_TZ prefix
+func WithLocation(location *time.Location) ScheduledProcessOption {
+	return func(process *ScheduledProcess) {
+		process.location = location
+	}
+}
@@ -0,0 +1,105 @@
+package manager
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScheduledProcess(t *testing.T) {
+	manager := newTestManager()
+
+	schedule, err := ParseSchedule("CRON_TZ=Europe/Lisbon */5 * * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := schedule.Next(time.Now()); next.Second()%5 != 0 {
+		t.Fatalf("unexpected next run %s", next)
+	}
+
+	if _, err := ParseSchedule("* * *"); err == nil {
+		t.Fatal("expected an invalid schedule")
+	}
+
+	var mux sync.Mutex
+	runs := 0
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	scheduled := make(chan struct{}, 1)
+	process := manager.NewScheduledProcess("interval", testSchedule{Schedule: Every(time.Millisecond), scheduled: scheduled}, func(ctx context.Context) error {
+		mux.Lock()
+		runs++
+		mux.Unlock()
+
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return nil
+	}, WithRunOnStart(true), WithOverlapPolicy(OverlapSkip))
+
+	if err := manager.AddProcess("interval", process); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	wait := func(c chan struct{}, message string) {
+		select {
+		case <-c:
+		case <-time.After(time.Second):
+			t.Fatal(message)
+		}
+	}
+
+	// the runs due while the first one is running are skipped
+	wait(started, "expected the first run")
+	for i := 0; i < 3; i++ {
+		wait(scheduled, "expected the next runs to be scheduled")
+	}
+
+	mux.Lock()
+	count := runs
+	mux.Unlock()
+	if count != 1 {
+		t.Fatalf("expected a single run while the first one is running, got %d", count)
+	}
+
+	if process.LastRun().IsZero() || process.NextRun().IsZero() {
+		t.Fatal("expected the last and next run times")
+	}
+
+	close(release)
+	wait(started, "expected more runs after the first one finished")
+
+	if err := manager.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	if process.Started() || !process.NextRun().IsZero() {
+		t.Fatal("expected the process to be stopped")
+	}
+}
+
+// testSchedule is a schedule that tells each time it schedules the next run
+type testSchedule struct {
+	Schedule
+	scheduled chan struct{}
+}
+
+func (schedule testSchedule) Next(t time.Time) time.Time {
+	select {
+	case schedule.scheduled <- struct{}{}:
+	default:
+	}
+
+	return schedule.Schedule.Next(t)
+}
@@ -0,0 +1,266 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/joaosoft/logger"
+)
+
+// RestartPolicy defines when a supervised process is restarted after its function returns
+type RestartPolicy string
+
+const (
+	// RestartNever runs the function once
+	RestartNever RestartPolicy = "never"
+	// RestartOnFailure restarts the function when it fails or panics
+	RestartOnFailure RestartPolicy = "on-failure"
+	// RestartAlways restarts the function whenever it returns, until the process is stopped
+	RestartAlways RestartPolicy = "always"
+)
+
+const (
+	defaultSupervisorMinBackoff  = 100 * time.Millisecond
+	defaultSupervisorMaxBackoff  = 30 * time.Second
+	defaultSupervisorJitter      = 0.2
+	defaultSupervisorMaxRestarts = 5
+	defaultSupervisorWindow      = time.Minute
+)
+
+// SupervisedProcess runs a function until it's stopped, restarting it by its restart policy
+// with an exponential backoff, and giving up when it's restarted too many times in a window of time
+type SupervisedProcess struct {
+	name        string
+	function    func(ctx context.Context) error
+	policy      RestartPolicy
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	jitter      float64
+	maxRestarts int
+	window      time.Duration
+	critical    bool
+	manager     *Manager
+	reporter    StateReporter
+	logger      logger.ILogger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	mux    sync.Mutex
+	state  lifecycleState
+}
+
+// NewSupervisedProcess ...
+func (manager *Manager) NewSupervisedProcess(name string, function func(ctx context.Context) error, options ...SupervisedProcessOption) *SupervisedProcess {
+	process := &SupervisedProcess{
+		name:        name,
+		function:    function,
+		policy:      RestartOnFailure,
+		minBackoff:  defaultSupervisorMinBackoff,
+		maxBackoff:  defaultSupervisorMaxBackoff,
+		jitter:      defaultSupervisorJitter,
+		maxRestarts: defaultSupervisorMaxRestarts,
+		window:      defaultSupervisorWindow,
+		manager:     manager,
+		logger:      manager.logger,
+	}
+	process.Reconfigure(options...)
+
+	return process
+}
+
+// Start ...
+func (process *SupervisedProcess) Start(ctx context.Context) error {
+	process.state.lock()
+	defer process.state.unlock()
+
+	if process.state.isStarted() {
+		return nil
+	}
+
+	// the function runs until the process is stopped, not until the start context is done
+	runCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	process.mux.Lock()
+	process.cancel = cancel
+	process.done = done
+	process.mux.Unlock()
+
+	process.state.setStarted(true)
+	go process.supervise(runCtx, done)
+
+	return nil
+}
+
+// Stop cancels the context of the function and waits for it to return
+func (process *SupervisedProcess) Stop(ctx context.Context) error {
+	process.state.lock()
+	defer process.state.unlock()
+
+	process.mux.Lock()
+	cancel, done := process.cancel, process.done
+	process.mux.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("timeout waiting for the process %s to stop: %s", process.name, ctx.Err())
+	}
+
+	process.mux.Lock()
+	process.cancel, process.done = nil, nil
+	process.mux.Unlock()
+
+	process.state.setStarted(false)
+
+	return nil
+}
+
+// Started ...
+func (process *SupervisedProcess) Started() bool {
+	return process.state.isStarted()
+}
+
+// SetStateReporter ...
+func (process *SupervisedProcess) SetStateReporter(reporter StateReporter) {
+	process.mux.Lock()
+	defer process.mux.Unlock()
+
+	process.reporter = reporter
+}
+
+// supervise runs the function and restarts it until the context is done or the process gives up
+func (process *SupervisedProcess) supervise(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	var restarts []time.Time
+	for {
+		err := process.run(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			process.logger.Errorf("error on process [ name: %s ]: %s", process.name, err)
+			process.report(StateFailed, err)
+		}
+
+		if !process.shouldRestart(err) {
+			if err == nil {
+				process.logger.Infof("process finished [ name: %s ]", process.name)
+				process.report(StateStopped, nil)
+			}
+			process.giveUp(err)
+			return
+		}
+
+		now := time.Now()
+		restarts = pruneRestarts(restarts, now.Add(-process.window))
+		if process.maxRestarts > 0 && len(restarts) >= process.maxRestarts {
+			err = fmt.Errorf("the process %s was restarted %d times in %s, giving up", process.name, len(restarts), process.window)
+			process.logger.Error(err)
+			process.report(StateFailed, err)
+			process.giveUp(err)
+			return
+		}
+
+		delay := process.backoff(len(restarts))
+		restarts = append(restarts, now)
+
+		process.logger.Infof("restarting process [ name: %s, restart: %d, backoff: %s ]", process.name, len(restarts), delay)
+		process.report(StateStarting, nil)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		process.report(StateRunning, nil)
+	}
+}
+
+// run executes the function, returning the panics as errors with their stack trace
+func (process *SupervisedProcess) run(ctx context.Context) error {
+	return runRecovered(ctx, process.function)
+}
+
+func (process *SupervisedProcess) shouldRestart(err error) bool {
+	switch process.policy {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return err != nil
+	default:
+		return false
+	}
+}
+
+// giveUp marks the process as stopped, stopping the manager when a critical process fails
+func (process *SupervisedProcess) giveUp(err error) {
+	process.state.setStarted(false)
+
+	if err != nil && process.critical {
+		process.manager.escalate(fmt.Errorf("the critical process %s failed: %w", process.name, err))
+	}
+}
+
+// backoff returns the delay before the next restart, doubling it for each recent restart
+func (process *SupervisedProcess) backoff(restarts int) time.Duration {
+	delay := process.minBackoff
+	for i := 0; i < restarts && delay < process.maxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > process.maxBackoff {
+		delay = process.maxBackoff
+	}
+
+	if process.jitter > 0 {
+		delay += time.Duration(rand.Float64() * process.jitter * float64(delay))
+	}
+
+	return delay
+}
+
+func (process *SupervisedProcess) report(state ComponentState, err error) {
+	process.mux.Lock()
+	reporter := process.reporter
+	process.mux.Unlock()
+
+	if reporter != nil {
+		reporter(state, err)
+	}
+}
+
+// pruneRestarts removes the restarts before the given time
+func pruneRestarts(restarts []time.Time, since time.Time) []time.Time {
+	i := 0
+	for i < len(restarts) && restarts[i].Before(since) {
+		i++
+	}
+
+	return restarts[i:]
+}
+
+// runRecovered executes the function, returning the panics as errors with their stack trace
+func runRecovered(ctx context.Context, function func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	return function(ctx)
+}
@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"time"
+)
+
+// SupervisedProcessOption ...
+type SupervisedProcessOption func(process *SupervisedProcess)
+
+// Reconfigure ...
+func (process *SupervisedProcess) Reconfigure(options ...SupervisedProcessOption) {
+	for _, option := range options {
+		option(process)
+	}
+}
+
+// WithRestartPolicy ...
+func WithRestartPolicy(policy RestartPolicy) SupervisedProcessOption {
+	return func(process *SupervisedProcess) {
+		process.policy = policy
+	}
+}
+
+// WithBackoff sets the delay before the first restart, doubled on each restart up to the max
+func WithBackoff(min, max time.Duration) SupervisedProcessOption {
+	return func(process *SupervisedProcess) {
+		process.minBackoff = min
+		process.maxBackoff = max
+	}
+}
+
+// WithJitter adds a random delay of up to the given fraction of the backoff, where zero disables it
+func WithJitter(jitter float64) SupervisedProcessOption {
+	return func(process *SupervisedProcess) {
+		process.jitter = jitter
+	}
+}
+
+// WithMaxRestarts gives up restarting the process when it's restarted the given times in the window,
+// where zero restarts disables the limit
+func WithMaxRestarts(restarts int, window time.Duration) SupervisedProcessOption {
+	return func(process *SupervisedProcess) {
+		process.maxRestarts = restarts
+		process.window = window
+	}
+}
+
+// WithCritical stops the manager when the process fails and isn't restarted anymore
+func WithCritical(critical bool) SupervisedProcessOption {
+	return func(process *SupervisedProcess) {
+		process.critical = critical
+	}
+}
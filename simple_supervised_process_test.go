@@ -0,0 +1,97 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSupervisedProcess(t *testing.T) {
+	manager := newTestManager()
+
+	var mux sync.Mutex
+	runs := 0
+	process := manager.NewSupervisedProcess("flaky", func(ctx context.Context) error {
+		mux.Lock()
+		runs++
+		run := runs
+		mux.Unlock()
+
+		if run == 1 {
+			panic("first run")
+		}
+		if run < 4 {
+			return fmt.Errorf("run %d failed", run)
+		}
+
+		<-ctx.Done()
+		return nil
+	}, WithBackoff(time.Millisecond, 10*time.Millisecond), WithJitter(0))
+
+	if err := manager.AddProcess("flaky", process); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		mux.Lock()
+		count := runs
+		mux.Unlock()
+
+		if count == 4 && manager.State(KindProcess, "flaky") == StateRunning {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the process to be restarted until it runs, got %d runs", count)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := manager.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	if process.Started() {
+		t.Fatal("expected the process to be stopped")
+	}
+}
+
+func TestSupervisedProcessEscalation(t *testing.T) {
+	manager := NewManager()
+
+	process := manager.NewSupervisedProcess("critical", func(ctx context.Context) error {
+		return fmt.Errorf("failed")
+	}, WithBackoff(time.Millisecond, time.Millisecond), WithMaxRestarts(2, time.Minute), WithCritical(true))
+
+	if err := manager.AddProcess("critical", process); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- manager.Start()
+	}()
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Fatal("expected the manager to stop with the error of the critical process")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the manager to stop when the critical process exhausts its restarts")
+	}
+
+	if manager.Started() {
+		t.Fatal("expected the manager to be stopped")
+	}
+
+	if state := manager.State(KindProcess, "critical"); state != StateFailed {
+		t.Fatalf("expected the critical process to be failed, got %s", state)
+	}
+}
@@ -1,7 +1,13 @@
 package manager
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
 	"sync"
+	"time"
 
 	"github.com/joaosoft/logger"
 
@@ -12,16 +18,25 @@ import (
 type SimpleWebServer struct {
 	server  *web.Server
 	host    string
+	metrics *Metrics
 	logger  logger.ILogger
-	started bool
+
+	// the requests in flight, where idle is closed when they finish while draining
+	inFlight int
+	idle     chan struct{}
+	drained  bool
+	mux      sync.Mutex
+	state    lifecycleState
 }
 
 // NewSimpleWebServer...
 func (manager *Manager) NewSimpleWebServer(host string) IWeb {
 	server, _ := web.NewServer(web.WithServerAddress(host))
 	return &SimpleWebServer{
-		server: server,
-		logger: manager.logger,
+		server:  server,
+		host:    host,
+		metrics: manager.metrics,
+		logger:  manager.logger,
 	}
 }
 
@@ -45,7 +60,12 @@ func (w *SimpleWebServer) AddRoute(method string, path string, handler HandlerFu
 		middlewares = append(middlewares, m.(web.MiddlewareFunc))
 	}
 
-	return w.server.AddRoute(web.Method(method), path, handler.(func(*web.Context) error), middlewares...)
+	return w.server.AddRoute(web.Method(method), path, w.instrument(method, path, handler.(func(*web.Context) error)), middlewares...)
+}
+
+// AddHttpHandler serves the standard http handler on the route
+func (w *SimpleWebServer) AddHttpHandler(method, path string, handler http.Handler) error {
+	return w.server.AddRoute(web.Method(method), path, w.instrument(method, path, serveHttpHandler(handler)))
 }
 
 // AddNamespace ...
@@ -70,7 +90,7 @@ func (w *SimpleWebServer) AddNamespace(path string, middleware []MiddlewareFunc,
 			middlewares = append(middlewares, m.(web.MiddlewareFunc))
 		}
 
-		if err = namespace.AddRoute(web.Method(route.Method), route.Path, route.Handler.(func(*web.Context) error), middlewares...); err != nil {
+		if err = namespace.AddRoute(web.Method(route.Method), route.Path, w.instrument(route.Method, path+route.Path, route.Handler.(func(*web.Context) error)), middlewares...); err != nil {
 			return err
 		}
 	}
@@ -78,6 +98,30 @@ func (w *SimpleWebServer) AddNamespace(path string, middleware []MiddlewareFunc,
 	return nil
 }
 
+// instrument observes the latency of the route handler and counts the requests in flight, waited by the drain
+func (w *SimpleWebServer) instrument(method, path string, handler func(*web.Context) error) func(*web.Context) error {
+	latency := routeLatency(w.metrics, w.host, method, path)
+
+	return func(ctx *web.Context) error {
+		w.mux.Lock()
+		w.inFlight++
+		w.mux.Unlock()
+
+		defer func() {
+			w.mux.Lock()
+			w.inFlight--
+			if w.inFlight == 0 && w.idle != nil {
+				close(w.idle)
+				w.idle = nil
+			}
+			w.mux.Unlock()
+		}()
+
+		defer latency.ObserveDuration(time.Now())
+		return handler(ctx)
+	}
+}
+
 func (w *SimpleWebServer) AddFilter(pattern string, position string, middleware MiddlewareFunc, method string, methods ...string) {
 	webMethods := make([]web.Method, 0)
 	for _, m := range methods {
@@ -88,60 +132,132 @@ func (w *SimpleWebServer) AddFilter(pattern string, position string, middleware
 }
 
 // Start ...
-func (w *SimpleWebServer) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
+func (w *SimpleWebServer) Start(ctx context.Context) error {
+	w.state.lock()
+	defer w.state.unlock()
 
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
-
-	if w.started {
+	if w.state.isStarted() {
 		return nil
 	}
 
+	w.drained = false
 	go w.server.Start()
-	w.started = true
+	w.state.setStarted(true)
 
 	return nil
 }
 
 // Stop ...
-func (w *SimpleWebServer) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
+func (w *SimpleWebServer) Stop(ctx context.Context) error {
+	w.state.lock()
+	defer w.state.unlock()
+
+	if !w.state.isStarted() {
+		return nil
+	}
 
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
+	// the server was already stopped by the drain
+	if !w.drained {
+		if err := w.server.Stop(); err != nil {
+			return err
+		}
 	}
 
-	defer wg.Done()
+	w.drained = false
+	w.state.setStarted(false)
+
+	return nil
+}
+
+// Drain stops the server from accepting new connections and waits for the requests in flight
+func (w *SimpleWebServer) Drain(ctx context.Context) error {
+	w.state.lock()
+	defer w.state.unlock()
 
-	if !w.started {
+	if !w.state.isStarted() {
 		return nil
 	}
 
-	if err := w.server.Stop(); err != nil {
-		return err
+	if !w.drained {
+		if err := w.server.Stop(); err != nil {
+			return err
+		}
+		w.drained = true
 	}
 
-	w.started = false
+	w.mux.Lock()
+	if w.inFlight == 0 {
+		w.mux.Unlock()
+		return nil
+	}
+	if w.idle == nil {
+		w.idle = make(chan struct{})
+	}
+	idle := w.idle
+	w.mux.Unlock()
 
-	return nil
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timeout waiting for the requests in flight [ host: %s ]: %s", w.host, ctx.Err())
+	}
 }
 
 // Started ...
 func (w *SimpleWebServer) Started() bool {
-	return w.started
+	return w.state.isStarted()
 }
 
 // GetClient ...
 func (w *SimpleWebServer) GetClient() interface{} {
 	return w.server
 }
+
+// serveHttpHandler calls the http handler with a http request made of the context,
+// and writes its response back to the context when it returns
+func serveHttpHandler(handler http.Handler) func(*web.Context) error {
+	return func(ctx *web.Context) error {
+		address := &url.URL{Path: ctx.Request.Address.Url, RawQuery: url.Values(ctx.Request.Params).Encode()}
+
+		request, err := http.NewRequest(string(ctx.Request.Method), address.String(), bytes.NewReader(ctx.Request.Body))
+		if err != nil {
+			return err
+		}
+
+		for name, values := range ctx.Request.Headers {
+			request.Header[http.CanonicalHeaderKey(name)] = values
+		}
+
+		writer := &webResponseWriter{header: make(http.Header), status: http.StatusOK}
+		handler.ServeHTTP(writer, request)
+
+		if ctx.Response.Headers == nil {
+			ctx.Response.Headers = make(web.Headers)
+		}
+		for name, values := range writer.header {
+			ctx.Response.Headers[name] = values
+		}
+
+		return ctx.Response.Bytes(web.Status(writer.status), web.ContentType(writer.header.Get("Content-Type")), writer.body.Bytes())
+	}
+}
+
+// webResponseWriter keeps the response of a http handler, to be written back to the context of the web
+type webResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (writer *webResponseWriter) Header() http.Header {
+	return writer.header
+}
+
+func (writer *webResponseWriter) Write(data []byte) (int, error) {
+	return writer.body.Write(data)
+}
+
+func (writer *webResponseWriter) WriteHeader(status int) {
+	writer.status = status
+}
@@ -1,7 +1,10 @@
 package manager
 
 import (
-	"sync"
+	"context"
+	"net"
+	"net/http"
+	"time"
 
 	"github.com/joaosoft/logger"
 
@@ -12,8 +15,9 @@ import (
 type SimpleWebEcho struct {
 	server  *echo.Echo
 	host    string
+	metrics *Metrics
 	logger  logger.ILogger
-	started bool
+	state   lifecycleState
 }
 
 // NewSimpleWebEcho...
@@ -22,9 +26,10 @@ func (manager *Manager) NewSimpleWebEcho(host string) IWeb {
 	e.HideBanner = true
 
 	return &SimpleWebEcho{
-		server: e,
-		host:   host,
-		logger: manager.logger,
+		server:  e,
+		host:    host,
+		metrics: manager.metrics,
+		logger:  manager.logger,
 	}
 }
 
@@ -43,13 +48,18 @@ func (w *SimpleWebEcho) AddRoutes(routes ...*Route) error {
 
 // AddRoute ...
 func (w *SimpleWebEcho) AddRoute(method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) error {
-	w.server.Add(method, path, handler.(func(echo.Context) error))
+	w.server.Add(method, path, w.instrument(method, path, handler.(func(echo.Context) error)))
 	for _, item := range middleware {
 		w.server.Group(path, item.(echo.MiddlewareFunc))
 	}
 	return nil
 }
 
+// AddHttpHandler ...
+func (w *SimpleWebEcho) AddHttpHandler(method, path string, handler http.Handler) error {
+	return w.AddRoute(method, path, (func(echo.Context) error)(echo.WrapHandler(handler)))
+}
+
 // AddNamespace ...
 func (w *SimpleWebEcho) AddNamespace(path string, middleware []MiddlewareFunc, routes ...*Route) error {
 
@@ -61,7 +71,7 @@ func (w *SimpleWebEcho) AddNamespace(path string, middleware []MiddlewareFunc, r
 	echoGroup := w.server.Group(path, middlewares...)
 
 	for _, route := range routes {
-		echoGroup.Add(route.Method, route.Path, route.Handler.(func(echo.Context) error))
+		echoGroup.Add(route.Method, route.Path, w.instrument(route.Method, path+route.Path, route.Handler.(func(echo.Context) error)))
 		for _, item := range route.Middlewares {
 			w.server.Group(path, item.(echo.MiddlewareFunc))
 		}
@@ -70,47 +80,55 @@ func (w *SimpleWebEcho) AddNamespace(path string, middleware []MiddlewareFunc, r
 	return nil
 }
 
+// instrument observes the latency of the route handler
+func (w *SimpleWebEcho) instrument(method, path string, handler echo.HandlerFunc) echo.HandlerFunc {
+	latency := routeLatency(w.metrics, w.host, method, path)
+
+	return func(ctx echo.Context) error {
+		defer latency.ObserveDuration(time.Now())
+		return handler(ctx)
+	}
+}
+
 func (w *SimpleWebEcho) AddFilter(pattern string, position string, middleware MiddlewareFunc, method string, methods ...string) {
 	// TODO: implement
 }
 
 // Start ...
-func (w *SimpleWebEcho) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
+func (w *SimpleWebEcho) Start(ctx context.Context) error {
+	w.state.lock()
+	defer w.state.unlock()
 
-	if w.started {
+	if w.state.isStarted() {
 		return nil
 	}
 
-	go w.server.Start(w.host)
-	w.started = true
+	listener, err := net.Listen("tcp", w.host)
+	if err != nil {
+		return err
+	}
+
+	// the server and the listener of echo can't serve again after they are closed, so each start takes new ones
+	w.server.Server = &http.Server{}
+	w.server.Listener = listener
+
+	go func() {
+		if err := w.server.Start(w.host); err != nil && err != http.ErrServerClosed {
+			w.logger.Errorf("error serving [ host: %s ]: %s", w.host, err)
+		}
+	}()
+
+	w.state.setStarted(true)
 
 	return nil
 }
 
 // Stop ...
-func (w *SimpleWebEcho) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
+func (w *SimpleWebEcho) Stop(ctx context.Context) error {
+	w.state.lock()
+	defer w.state.unlock()
 
-	if !w.started {
+	if !w.state.isStarted() {
 		return nil
 	}
 
@@ -118,14 +136,26 @@ func (w *SimpleWebEcho) Stop(waitGroup ...*sync.WaitGroup) error {
 		return err
 	}
 
-	w.started = false
+	w.state.setStarted(false)
 
 	return nil
 }
 
+// Drain stops accepting new connections and waits for the requests in flight
+func (w *SimpleWebEcho) Drain(ctx context.Context) error {
+	w.state.lock()
+	defer w.state.unlock()
+
+	if !w.state.isStarted() {
+		return nil
+	}
+
+	return w.server.Shutdown(ctx)
+}
+
 // Started ...
 func (w *SimpleWebEcho) Started() bool {
-	return w.started
+	return w.state.isStarted()
 }
 
 // GetClient ...
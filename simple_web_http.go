@@ -1,8 +1,12 @@
 package manager
 
 import (
+	"context"
+	"fmt"
+	"net"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/joaosoft/logger"
 )
@@ -10,18 +14,30 @@ import (
 // SimpleWebHttp ...
 type SimpleWebHttp struct {
 	server  *http.Server
+	mux     *http.ServeMux
 	handler *HandlerFunc
+
+	// the handlers of the routes by path and method, as the mux only routes by path
+	routes    map[string]map[string]http.HandlerFunc
+	routesMux sync.RWMutex
+
 	host    string
+	metrics *Metrics
 	logger  logger.ILogger
-	started bool
+	state   lifecycleState
 }
 
 // NewSimpleWebHttp...
 func (manager *Manager) NewSimpleWebHttp(host string) IWeb {
+	mux := http.NewServeMux()
+
 	return &SimpleWebHttp{
-		server: &http.Server{Addr: host},
-		host:   host,
-		logger: manager.logger,
+		server:  &http.Server{Addr: host, Handler: mux},
+		mux:     mux,
+		routes:  make(map[string]map[string]http.HandlerFunc),
+		host:    host,
+		metrics: manager.metrics,
+		logger:  manager.logger,
 	}
 }
 
@@ -40,10 +56,55 @@ func (w *SimpleWebHttp) AddRoutes(routes ...*Route) error {
 
 // AddRoute ...
 func (w *SimpleWebHttp) AddRoute(method, path string, handler HandlerFunc, middleware ...MiddlewareFunc) error {
-	http.HandleFunc(path, handler.(func(http.ResponseWriter, *http.Request)))
+	function := handler.(func(http.ResponseWriter, *http.Request))
+	latency := routeLatency(w.metrics, w.host, method, path)
+
+	w.routesMux.Lock()
+	defer w.routesMux.Unlock()
+
+	methods, exists := w.routes[path]
+	if !exists {
+		methods = make(map[string]http.HandlerFunc)
+		w.routes[path] = methods
+		w.mux.HandleFunc(path, w.dispatch(path))
+	}
+
+	if _, exists := methods[method]; exists {
+		return fmt.Errorf("the route %s %s already exists", method, path)
+	}
+
+	methods[method] = func(writer http.ResponseWriter, request *http.Request) {
+		defer latency.ObserveDuration(time.Now())
+		function(writer, request)
+	}
+
 	return nil
 }
 
+// dispatch serves the request with the handler of its method on the path, where an empty method is any method
+func (w *SimpleWebHttp) dispatch(path string) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		w.routesMux.RLock()
+		handler, exists := w.routes[path][request.Method]
+		if !exists {
+			handler, exists = w.routes[path][""]
+		}
+		w.routesMux.RUnlock()
+
+		if !exists {
+			http.Error(writer, fmt.Sprintf("method %s not allowed", request.Method), http.StatusMethodNotAllowed)
+			return
+		}
+
+		handler(writer, request)
+	}
+}
+
+// AddHttpHandler ...
+func (w *SimpleWebHttp) AddHttpHandler(method, path string, handler http.Handler) error {
+	return w.AddRoute(method, path, handler.ServeHTTP)
+}
+
 // AddNamespace ...
 func (w *SimpleWebHttp) AddNamespace(path string, middleware []MiddlewareFunc, routes ...*Route) error {
 	return nil
@@ -54,43 +115,40 @@ func (w *SimpleWebHttp) AddFilter(pattern string, position string, middleware Mi
 }
 
 // Start ...
-func (w *SimpleWebHttp) Start(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
+func (w *SimpleWebHttp) Start(ctx context.Context) error {
+	w.state.lock()
+	defer w.state.unlock()
 
-	defer wg.Done()
-
-	if w.started {
+	if w.state.isStarted() {
 		return nil
 	}
 
-	go http.ListenAndServe(w.host, nil)
+	listener, err := net.Listen("tcp", w.host)
+	if err != nil {
+		return err
+	}
+
+	// a server can't serve again after it's closed, so each start takes a new one
+	server := &http.Server{Addr: w.host, Handler: w.mux}
+	w.server = server
+
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			w.logger.Errorf("error serving [ host: %s ]: %s", w.host, err)
+		}
+	}()
 
-	w.started = true
+	w.state.setStarted(true)
 
 	return nil
 }
 
 // Stop ...
-func (w *SimpleWebHttp) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
-
-	defer wg.Done()
+func (w *SimpleWebHttp) Stop(ctx context.Context) error {
+	w.state.lock()
+	defer w.state.unlock()
 
-	if !w.started {
+	if !w.state.isStarted() {
 		return nil
 	}
 
@@ -98,14 +156,26 @@ func (w *SimpleWebHttp) Stop(waitGroup ...*sync.WaitGroup) error {
 		return err
 	}
 
-	w.started = false
+	w.state.setStarted(false)
 
 	return nil
 }
 
+// Drain stops accepting new connections and waits for the requests in flight
+func (w *SimpleWebHttp) Drain(ctx context.Context) error {
+	w.state.lock()
+	defer w.state.unlock()
+
+	if !w.state.isStarted() {
+		return nil
+	}
+
+	return w.server.Shutdown(ctx)
+}
+
 // Started ...
 func (w *SimpleWebHttp) Started() bool {
-	return w.started
+	return w.state.isStarted()
 }
 
 // GetClient ...
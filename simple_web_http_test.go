@@ -0,0 +1,62 @@
+package manager
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestSimpleWebHttpRestart(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	host := listener.Addr().String()
+	listener.Close()
+
+	w := newTestManager().NewSimpleWebHttp(host)
+	if err := w.AddRoute(http.MethodGet, "/ping", func(writer http.ResponseWriter, request *http.Request) {
+		writer.Write([]byte("pong"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	web := w.(*SimpleWebHttp)
+	for i := 0; i < 2; i++ {
+		if err := web.Start(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		response, err := http.Get("http://" + host + "/ping")
+		if err != nil {
+			t.Fatalf("expected the web to serve on the start %d: %s", i+1, err)
+		}
+		body, _ := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+
+		if string(body) != "pong" {
+			t.Fatalf("unexpected body %q on the start %d", body, i+1)
+		}
+
+		// the drain shuts the server down, so the next start must take a new one
+		if err := web.Drain(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if err := web.Stop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the start fails when the host can't be listened
+	busy, err := net.Listen("tcp", host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+
+	if err := web.Start(context.Background()); err == nil || web.Started() {
+		t.Fatal("expected the start to fail on a host in use")
+	}
+}
@@ -0,0 +1,88 @@
+package manager
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/joaosoft/web"
+)
+
+func TestSimpleWebServerDrain(t *testing.T) {
+	w := newTestManager().NewSimpleWebServer("localhost:0").(*SimpleWebServer)
+	if err := w.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	entered, release := make(chan struct{}), make(chan struct{})
+	handler := w.instrument("GET", "/slow", func(ctx *web.Context) error {
+		close(entered)
+		<-release
+		return nil
+	})
+
+	go handler(nil)
+	<-entered
+
+	// the drain waits for the request in flight
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := w.Drain(ctx); err == nil {
+		t.Fatal("expected the drain to time out while the request is in flight")
+	}
+
+	drained := make(chan error, 1)
+	go func() { drained <- w.Drain(context.Background()) }()
+	close(release)
+
+	select {
+	case err := <-drained:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the drain to finish with the request in flight")
+	}
+
+	if err := w.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if w.Started() {
+		t.Fatal("expected the web to be stopped")
+	}
+}
+
+func TestSimpleWebServerHttpHandler(t *testing.T) {
+	handler := serveHttpHandler(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/admin/components" || request.URL.Query().Get("kind") != "db" {
+			writer.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusAccepted)
+		writer.Write([]byte(request.Header.Get("X-Request-Id")))
+	}))
+
+	ctx := &web.Context{
+		Request: &web.Request{Base: web.Base{
+			Method:  web.Method(http.MethodGet),
+			Address: &web.Address{Url: "/admin/components"},
+			Headers: web.Headers{"x-request-id": {"42"}},
+			Params:  web.Params{"kind": {"db"}},
+		}},
+		Response: &web.Response{},
+	}
+
+	if err := handler(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if ctx.Response.Status != web.Status(http.StatusAccepted) || string(ctx.Response.Body) != "42" {
+		t.Fatalf("unexpected response [ status: %d, body: %s ]", ctx.Response.Status, ctx.Response.Body)
+	}
+	if got := ctx.Response.Headers["Content-Type"]; len(got) != 1 || got[0] != "application/json" {
+		t.Fatalf("unexpected content type %v", got)
+	}
+}
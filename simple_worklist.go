@@ -1,11 +1,16 @@
 package manager
 
 import (
-	"sync"
+	"context"
+	"fmt"
+	"time"
 
 	"github.com/joaosoft/logger"
 )
 
+// drainInterval is the interval to check if the list is empty while draining
+const drainInterval = 100 * time.Millisecond
+
 // SimpleWorkList ...
 type SimpleWorkList struct {
 	name                            string
@@ -13,39 +18,37 @@ type SimpleWorkList struct {
 	handler                         WorkHandler
 	workRecoverHandler              WorkRecoverHandler
 	workRecoverWastedRetriesHandler WorkRecoverWastedRetriesHandler
+	workDrainHandler                WorkDrainHandler
 	list                            IList
 	workers                         []*Worker
+	metrics                         *workMetrics
 	logger                          logger.ILogger
-	started                         bool
+	state                           lifecycleState
 }
 
 // NewSimpleWorkList ...
-func (manager *Manager) NewSimpleWorkList(config *WorkListConfig, handler WorkHandler, workRecoverHandler WorkRecoverHandler, workRecoverWastedRetriesHandler WorkRecoverWastedRetriesHandler) IWorkList {
-	return &SimpleWorkList{
+func (manager *Manager) NewSimpleWorkList(config *WorkListConfig, handler WorkHandler, workRecoverHandler WorkRecoverHandler, workRecoverWastedRetriesHandler WorkRecoverWastedRetriesHandler, options ...SimpleWorkListOption) IWorkList {
+	worklist := &SimpleWorkList{
 		name:                            config.Name,
-		list:                            manager.NewQueue(WithMode(config.Mode)),
+		list:                            manager.NewQueue(WithMode(config.Mode), WithName(config.Name)),
 		config:                          config,
 		handler:                         handler,
 		workRecoverHandler:              workRecoverHandler,
 		workRecoverWastedRetriesHandler: workRecoverWastedRetriesHandler,
+		metrics:                         newWorkMetrics(manager.metrics, config.Name),
 		logger:                          manager.logger,
 	}
+	worklist.Reconfigure(options...)
+
+	return worklist
 }
 
 // Start ...
-func (s *SimpleWorkList) Start(waitGroup ...*sync.WaitGroup) (err error) {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
-	}
+func (s *SimpleWorkList) Start(ctx context.Context) (err error) {
+	s.state.lock()
+	defer s.state.unlock()
 
-	defer wg.Done()
-
-	if s.started {
+	if s.state.isStarted() {
 		return nil
 	}
 
@@ -53,6 +56,7 @@ func (s *SimpleWorkList) Start(waitGroup ...*sync.WaitGroup) (err error) {
 	for i := 1; i <= s.config.MaxWorkers; i++ {
 		s.logger.Infof("starting worker [ %d ]", i)
 		worker := NewWorker(i, s.config, s.handler, s.list, s.workRecoverHandler, s.workRecoverWastedRetriesHandler, s.logger)
+		worker.metrics = s.metrics
 
 		if err = worker.Start(); err != nil {
 			s.logger.Errorf("error starting worker [ %d: %s ]: %s", worker.id, worker.name, err)
@@ -70,25 +74,30 @@ func (s *SimpleWorkList) Start(waitGroup ...*sync.WaitGroup) (err error) {
 	}
 
 	s.workers = workers
-	s.started = true
+	s.state.setStarted(true)
 
 	return nil
 }
 
-// Stop ...
-func (s *SimpleWorkList) Stop(waitGroup ...*sync.WaitGroup) error {
-	var wg *sync.WaitGroup
-
-	if len(waitGroup) == 0 {
-		wg = &sync.WaitGroup{}
-		wg.Add(1)
-	} else {
-		wg = waitGroup[0]
+// Drain waits for the workers to process the work in the list, giving the work left to the drain handler
+// when the context is done
+func (s *SimpleWorkList) Drain(ctx context.Context) error {
+	s.state.lock()
+	defer s.state.unlock()
+
+	if !s.state.isStarted() {
+		return nil
 	}
 
-	defer wg.Done()
+	return drainList(ctx, s.name, s.list, s.workDrainHandler, s.logger)
+}
+
+// Stop ...
+func (s *SimpleWorkList) Stop(ctx context.Context) error {
+	s.state.lock()
+	defer s.state.unlock()
 
-	if !s.started {
+	if !s.state.isStarted() {
 		return nil
 	}
 
@@ -99,14 +108,65 @@ func (s *SimpleWorkList) Stop(waitGroup ...*sync.WaitGroup) error {
 		}
 	}
 
-	s.started = false
+	s.state.setStarted(false)
 
 	return nil
 }
 
+// SetMaxWorkers changes the number of workers, starting or stopping the difference when the list is started,
+// as when the max workers of its config changes
+func (s *SimpleWorkList) SetMaxWorkers(maxWorkers int) error {
+	if maxWorkers < 1 {
+		return fmt.Errorf("invalid max workers %d, it should be at least 1", maxWorkers)
+	}
+
+	s.state.lock()
+	defer s.state.unlock()
+
+	s.logger.Infof("changing the max workers [ name: %s, from: %d, to: %d ]", s.name, s.config.MaxWorkers, maxWorkers)
+	s.config.MaxWorkers = maxWorkers
+
+	if !s.state.isStarted() {
+		return nil
+	}
+
+	for len(s.workers) < maxWorkers {
+		id := len(s.workers) + 1
+		s.logger.Infof("starting worker [ %d ]", id)
+		worker := NewWorker(id, s.config, s.handler, s.list, s.workRecoverHandler, s.workRecoverWastedRetriesHandler, s.logger)
+		worker.metrics = s.metrics
+
+		if err := worker.Start(); err != nil {
+			return err
+		}
+		s.workers = append(s.workers, worker)
+	}
+
+	for len(s.workers) > maxWorkers {
+		worker := s.workers[len(s.workers)-1]
+		s.logger.Infof("stopping worker [ %d: %s ]", worker.id, worker.name)
+		if err := worker.Stop(); err != nil {
+			s.logger.Errorf("error stopping worker [ %d: %s ]: %s", worker.id, worker.name, err)
+		}
+		s.workers = s.workers[:len(s.workers)-1]
+	}
+
+	return nil
+}
+
+// Healthy ...
+func (s *SimpleWorkList) Healthy(ctx context.Context) error {
+	return checkListSize(s.list, s.config.HealthThreshold)
+}
+
 // Started ...
 func (s *SimpleWorkList) Started() bool {
-	return s.started
+	return s.state.isStarted()
+}
+
+// Dump ...
+func (s *SimpleWorkList) Dump() string {
+	return s.list.Dump()
 }
 
 // AddWork ...
@@ -115,3 +175,32 @@ func (s *SimpleWorkList) AddWork(id string, data interface{}) {
 	work := NewWork(id, data, s.logger)
 	s.list.Add(id, work)
 }
+
+// drainList waits for the list to be empty, giving the work left to the handler when the context is done
+func drainList(ctx context.Context, name string, list IList, handler func(list IList) error, logger logger.ILogger) error {
+	ticker := time.NewTicker(drainInterval)
+	defer ticker.Stop()
+
+	for !list.IsEmpty() {
+		select {
+		case <-ctx.Done():
+			logger.Infof("work left in the list after draining [ name: %s, list size: %d ]", name, list.Size())
+			if handler != nil {
+				return handler(list)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	return nil
+}
+
+// checkListSize fails when the list size is above the threshold, where zero disables the check
+func checkListSize(list IList, threshold int) error {
+	if size := list.Size(); threshold > 0 && size > threshold {
+		return fmt.Errorf("the list size %d is above the threshold %d", size, threshold)
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package manager
+
+// SimpleWorkListOption ...
+type SimpleWorkListOption func(worklist *SimpleWorkList)
+
+// Reconfigure ...
+func (s *SimpleWorkList) Reconfigure(options ...SimpleWorkListOption) {
+	for _, option := range options {
+		option(s)
+	}
+}
+
+// WithWorkDrainHandler ...
+func WithWorkDrainHandler(handler WorkDrainHandler) SimpleWorkListOption {
+	return func(worklist *SimpleWorkList) {
+		worklist.workDrainHandler = handler
+	}
+}
+
+// SimpleBulkWorkListOption ...
+type SimpleBulkWorkListOption func(bulkWorklist *SimpleBulkWorkList)
+
+// Reconfigure ...
+func (bulkWorklist *SimpleBulkWorkList) Reconfigure(options ...SimpleBulkWorkListOption) {
+	for _, option := range options {
+		option(bulkWorklist)
+	}
+}
+
+// WithBulkWorkDrainHandler ...
+func WithBulkWorkDrainHandler(handler BulkWorkDrainHandler) SimpleBulkWorkListOption {
+	return func(bulkWorklist *SimpleBulkWorkList) {
+		bulkWorklist.bulkWorkDrainHandler = handler
+	}
+}
@@ -28,6 +28,7 @@ func Exists(file string) bool {
 	return true
 }
 
+// ReadFile reads the file, unmarshalling the .json and .yaml files into the object when it is given
 func ReadFile(fileName string, obj interface{}) ([]byte, error) {
 	var err error
 
@@ -0,0 +1,40 @@
+package manager
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadFile(t *testing.T) {
+	type service struct {
+		MaxWorkers int `yaml:"max-workers" json:"max_workers"`
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"service.yaml": "max-workers: 5\n",
+		"service.json": `{"max_workers": 5}`,
+		"service.ini":  "max_workers = 5",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the yaml is bound by the yaml tags and the json by the json tags
+	for _, name := range []string{"service.yaml", "service.json"} {
+		obj := &service{}
+		if _, err := ReadFile(filepath.Join(dir, name), obj); err != nil || obj.MaxWorkers != 5 {
+			t.Fatalf("expected the max workers of %s, got %d: %v", name, obj.MaxWorkers, err)
+		}
+	}
+
+	// the other files are only read
+	obj := &service{}
+	data, err := ReadFile(filepath.Join(dir, "service.ini"), obj)
+	if err != nil || string(data) != files["service.ini"] || obj.MaxWorkers != 0 {
+		t.Fatalf("expected the file to be read without unmarshalling it, got %q %d: %v", data, obj.MaxWorkers, err)
+	}
+}
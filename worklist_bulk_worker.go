@@ -16,6 +16,9 @@ type BulkWorkRecoverHandler func(list IList) error
 // BulkWorkRecoverWastedRetriesHandler ...
 type BulkWorkRecoverWastedRetriesHandler func(id string, data interface{}) error
 
+// BulkWorkDrainHandler receives the work left in the list when the drain deadline is reached, to persist it
+type BulkWorkDrainHandler func(list IList) error
+
 // Worker ...
 type BulkWorker struct {
 	id                          int
@@ -29,6 +32,7 @@ type BulkWorker struct {
 	sleepTime                   time.Duration
 	quit                        chan bool
 	mux                         *sync.Mutex
+	metrics                     *workMetrics
 	logger                      logger.ILogger
 	started                     bool
 }
@@ -75,7 +79,9 @@ func (bulkWorker *BulkWorker) Start() error {
 		}
 	}()
 
+	bulkWorker.mux.Lock()
 	bulkWorker.started = true
+	bulkWorker.mux.Unlock()
 
 	return nil
 }
@@ -116,23 +122,33 @@ func (bulkWorker *BulkWorker) execute() error {
 
 	var works []*Work
 	for i := 0; i < bulkWorker.maxWorks; i++ {
-		if tmp := bulkWorker.list.Remove(); tmp != nil {
-			if tmp == nil {
-				break
-			}
-			works = append(works, tmp.(*Work))
+		tmp := bulkWorker.list.Remove()
+		if tmp == nil {
+			break
 		}
+		works = append(works, tmp.(*Work))
 	}
 
-	if err := bulkWorker.handler(works); err != nil {
+	// another worker can take the last works between the check of the size and the remove
+	if len(works) == 0 {
+		return nil
+	}
+
+	start := time.Now()
+	err := bulkWorker.handler(works)
+	bulkWorker.metrics.observe(start, len(works), err)
+
+	if err != nil {
 		for _, work := range works {
 			if work.retries < bulkWorker.maxRetries {
 				work.retries++
+				bulkWorker.metrics.requeue(1)
 				if err := bulkWorker.list.Add(work.Id, work); err != nil {
 					logger.Errorf("error processing the work. re-adding the work to the list [retries: %d, error: %s ]", work.retries, err)
 				}
 				logger.Errorf("work requeued of the queue [ retries: %d, error: %s ]", work.retries, err).ToError()
 			} else {
+				bulkWorker.metrics.discard(1)
 				if bulkWorker.recoverWastedRetriesHandler != nil {
 					if err := bulkWorker.recoverWastedRetriesHandler(work.Id, work.Data); err != nil {
 						logger.Errorf("error processing recovering one of worker. [ error: %s ]", err).ToError()
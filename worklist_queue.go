@@ -34,18 +34,27 @@ type Queue struct {
 	maxSize int
 	mux     *sync.Mutex
 	ids     map[string]*Node
-	logger logger.ILogger
+	name    string
+	adds    *Counter
+	removes *Counter
+	sizes   *Gauge
+	logger  logger.ILogger
 }
 
 // NewQueue ...
 func (manager *Manager) NewQueue(options ...QueueOption) IList {
 	queue := &Queue{
-		ids: make(map[string]*Node),
-		mux: &sync.Mutex{},
+		ids:    make(map[string]*Node),
+		mux:    &sync.Mutex{},
 		logger: manager.logger,
 	}
 	queue.Reconfigure(options...)
 
+	labels := Labels{"queue": queue.name}
+	queue.adds = manager.metrics.Counter("manager_queue_adds_total", "Number of works added to the queue.", labels)
+	queue.removes = manager.metrics.Counter("manager_queue_removes_total", "Number of works removed from the queue.", labels)
+	queue.sizes = manager.metrics.Gauge("manager_queue_size", "Number of works in the queue.", labels)
+
 	return queue
 }
 
@@ -69,6 +78,10 @@ func (queue *Queue) Add(id string, data interface{}) error {
 	}
 	queue.ids[id] = nodeToAdd
 	queue.size++
+
+	queue.adds.Inc()
+	queue.sizes.Set(float64(queue.size))
+
 	return nil
 }
 
@@ -80,6 +93,13 @@ func (queue *Queue) Remove(ids ...string) interface{} {
 	if queue.size == 0 {
 		return nil
 	}
+
+	sizeBefore := queue.size
+	defer func() {
+		queue.removes.Add(float64(sizeBefore - queue.size))
+		queue.sizes.Set(float64(queue.size))
+	}()
+
 	var nodeToRemove *Node
 	if len(ids) == 0 {
 		switch queue.mode {
@@ -121,8 +141,6 @@ func (queue *Queue) Remove(ids ...string) interface{} {
 		}
 		return nodesRemoved
 	}
-
-	return nil
 }
 
 // Size ...
@@ -141,6 +159,9 @@ func (queue *Queue) IsEmpty() bool {
 
 // Dump ...
 func (queue *Queue) Dump() string {
+	queue.mux.Lock()
+	defer queue.mux.Unlock()
+
 	type queuePrint struct {
 		Size    int              `json:"size"`
 		Mode    Mode             `json:"mode"`
@@ -17,6 +17,13 @@ func WithMode(mode Mode) QueueOption {
 	}
 }
 
+// WithName sets the name of the queue, used on its metrics
+func WithName(name string) QueueOption {
+	return func(queue *Queue) {
+		queue.name = name
+	}
+}
+
 // WithMaxSize ...
 func WithMaxSize(size int) QueueOption {
 	return func(queue *Queue) {
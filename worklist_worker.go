@@ -25,6 +25,9 @@ type WorkRecoverHandler func(list IList) error
 // WorkRecoverWastedRetriesHandler ...
 type WorkRecoverWastedRetriesHandler func(id string, data interface{}) error
 
+// WorkDrainHandler receives the work left in the list when the drain deadline is reached, to persist it
+type WorkDrainHandler func(list IList) error
+
 // Worker ...
 type Worker struct {
 	id                              int
@@ -37,6 +40,7 @@ type Worker struct {
 	sleepTime                       time.Duration
 	quit                            chan bool
 	mux                             *sync.Mutex
+	metrics                         *workMetrics
 	logger                          logger.ILogger
 	started                         bool
 }
@@ -84,7 +88,9 @@ func (worker *Worker) Start() error {
 		}
 	}()
 
+	worker.mux.Lock()
 	worker.started = true
+	worker.mux.Unlock()
 
 	return nil
 }
@@ -112,8 +118,6 @@ func (worker *Worker) AddWork(id string, data interface{}) error {
 }
 
 func (worker *Worker) execute() error {
-	var work *Work
-
 	defer func() {
 		if worker.workRecoverHandler != nil {
 			if r := recover(); r != nil {
@@ -125,19 +129,28 @@ func (worker *Worker) execute() error {
 		}
 	}()
 
-	if tmp := worker.list.Remove(); tmp != nil {
-		work = tmp.(*Work)
+	// another worker can take the last work between the check of the size and the remove
+	tmp := worker.list.Remove()
+	if tmp == nil {
+		return nil
 	}
+	work := tmp.(*Work)
+
+	start := time.Now()
+	err := worker.handler(work.Id, work.Data)
+	worker.metrics.observe(start, 1, err)
 
-	if err := worker.handler(work.Id, work.Data); err != nil {
+	if err != nil {
 		if work.retries < worker.maxRetries {
 			work.retries++
+			worker.metrics.requeue(1)
 			if err := worker.list.Add(work.Id, work); err != nil {
 				logger.Errorf("error processing the work. re-adding the work to the list [retries: %d, error: %s ]", work.retries, err).ToError()
 			}
 			logger.Errorf("work requeued of the queue [ retries: %d, error: %s ]", work.retries, err).ToError()
 
 		} else {
+			worker.metrics.discard(1)
 			if worker.workRecoverWastedRetriesHandler != nil {
 				if err := worker.workRecoverWastedRetriesHandler(work.Id, work.Data); err != nil {
 					logger.Errorf("error processing recovering one of worker. [ error: %s ]", err).ToError()
@@ -0,0 +1,28 @@
+package manager
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWorkerEmptyList(t *testing.T) {
+	manager := newTestManager()
+
+	// the list can be emptied by another worker after it was checked
+	var handled int
+	worker := NewWorker(1, NewWorkListConfig("jobs", 2, 1, time.Millisecond, FIFO),
+		func(id string, data interface{}) error { handled++; return nil }, manager.NewQueue(), nil, nil, manager.logger)
+	if err := worker.execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	bulkWorker := NewBulkWorker(1, NewBulkWorkListConfig("bulk_jobs", 10, 2, 1, time.Millisecond, FIFO),
+		func(works []*Work) error { handled++; return nil }, manager.NewQueue(), nil, nil, manager.logger)
+	if err := bulkWorker.execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	if handled != 0 {
+		t.Fatalf("expected the handlers not to be called without work, got %d calls", handled)
+	}
+}